@@ -3,20 +3,30 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"sort"
 	"strings"
 
 	"github.com/kluzzebass/reflag/translator"
-	_ "github.com/kluzzebass/reflag/translator/bat2cat"   // Register bat2cat translator
-	_ "github.com/kluzzebass/reflag/translator/df2duf"    // Register df2duf translator
-	_ "github.com/kluzzebass/reflag/translator/dig2doggo" // Register dig2doggo translator
-	_ "github.com/kluzzebass/reflag/translator/du2dust"   // Register du2dust translator
-	_ "github.com/kluzzebass/reflag/translator/find2fd"   // Register find2fd translator
-	_ "github.com/kluzzebass/reflag/translator/grep2rg"   // Register grep2rg translator
-	_ "github.com/kluzzebass/reflag/translator/less2moor" // Register less2moor translator
-	_ "github.com/kluzzebass/reflag/translator/ls2eza"    // Register ls2eza translator
-	_ "github.com/kluzzebass/reflag/translator/more2moor" // Register more2moor translator
-	_ "github.com/kluzzebass/reflag/translator/ps2procs"  // Register ps2procs translator
+	_ "github.com/kluzzebass/reflag/translator/bat2cat"     // Register bat2cat translator
+	_ "github.com/kluzzebass/reflag/translator/df2duf"      // Register df2duf translator
+	_ "github.com/kluzzebass/reflag/translator/dig2doggo"   // Register dig2doggo translator
+	_ "github.com/kluzzebass/reflag/translator/du2dust"     // Register du2dust translator
+	_ "github.com/kluzzebass/reflag/translator/egrep2rg"    // Register egrep2rg translator
+	_ "github.com/kluzzebass/reflag/translator/fgrep2rg"    // Register fgrep2rg translator
+	_ "github.com/kluzzebass/reflag/translator/find2fd"     // Register find2fd translator
+	_ "github.com/kluzzebass/reflag/translator/grep2rg"     // Register grep2rg translator
+	_ "github.com/kluzzebass/reflag/translator/less2moor"   // Register less2moor translator
+	_ "github.com/kluzzebass/reflag/translator/ls2eza"      // Register ls2eza translator
+	_ "github.com/kluzzebass/reflag/translator/ls2lsd"      // Register ls2lsd translator
+	_ "github.com/kluzzebass/reflag/translator/more2moor"   // Register more2moor translator
+	_ "github.com/kluzzebass/reflag/translator/pgrep2procs" // Register pgrep2procs translator
+	_ "github.com/kluzzebass/reflag/translator/pidof2procs" // Register pidof2procs translator
+	_ "github.com/kluzzebass/reflag/translator/ps2procs"    // Register ps2procs translator
+	_ "github.com/kluzzebass/reflag/translator/top2btm"     // Register top2btm translator
+	_ "github.com/kluzzebass/reflag/translator/top2procs"   // Register top2procs translator
+	_ "github.com/kluzzebass/reflag/translator/zegrep2rg"   // Register zegrep2rg translator
+	_ "github.com/kluzzebass/reflag/translator/zgrep2rg"    // Register zgrep2rg translator
 )
 
 // Version information - set via ldflags at build time
@@ -27,7 +37,7 @@ var (
 )
 
 func shellQuote(s string) string {
-	if strings.ContainsAny(s, " \t\n\"'\\$`!") {
+	if strings.ContainsAny(s, " \t\n\"'\\$`!;&|<>()*?[]{}#~") {
 		return "'" + strings.ReplaceAll(s, "'", "'\"'\"'") + "'"
 	}
 	return s
@@ -70,10 +80,12 @@ SOFTWARE.`
 	fmt.Println(licenseText)
 }
 
-// parseInitArgs parses --init arguments, returning shell type and add/remove lists
+// parseInitArgs parses --init arguments, returning shell type, add/remove lists
+// and target preferences
 // Shell can appear anywhere in args; defaults to "bash" if not specified
 // Arguments starting with + are added to defaults, - are removed from defaults
-func parseInitArgs(args []string) (shell string, add []string, remove []string) {
+// Arguments like ls:eza,lsd,ls set the order in which targets are tried for a source tool
+func parseInitArgs(args []string) (shell string, add []string, remove []string, prefer map[string][]string) {
 	shell = "bash"
 	for _, arg := range args {
 		switch {
@@ -83,12 +95,21 @@ func parseInitArgs(args []string) (shell string, add []string, remove []string)
 			add = append(add, strings.TrimPrefix(arg, "+"))
 		case strings.HasPrefix(arg, "-"):
 			remove = append(remove, strings.TrimPrefix(arg, "-"))
+		case strings.Contains(arg, ":"):
+			source, targets, _ := strings.Cut(arg, ":")
+			if prefer == nil {
+				prefer = make(map[string][]string)
+			}
+			prefer[source] = strings.Split(targets, ",")
 		}
 	}
 	return
 }
 
-func printInit(shell string, add []string, remove []string) {
+// initChains returns the targets to try for each source tool, in order
+// A target equal to its source tool falls back to the original command
+// It's an error for a source tool to have several translators without a preference
+func initChains(add []string, remove []string, prefer map[string][]string) (map[string][]string, error) {
 	// Start with default translators
 	nameSet := make(map[string]bool)
 	for _, name := range translator.List() {
@@ -116,37 +137,79 @@ func printInit(shell string, add []string, remove []string) {
 		}
 	}
 
-	// Convert to sorted slice
-	var names []string
+	// Group the enabled translators by source tool
+	chains := make(map[string][]string)
 	for name := range nameSet {
-		names = append(names, name)
+		t := translator.GetByName(name)
+		chains[t.SourceTool()] = append(chains[t.SourceTool()], t.TargetTool())
+	}
+
+	// Preferences replace the chain and enable the translators they name
+	for source, targets := range prefer {
+		for _, target := range targets {
+			if target != source && translator.Get(source, target) == nil {
+				return nil, fmt.Errorf("no translator registered for %s to %s", source, target)
+			}
+		}
+		chains[source] = targets
+	}
+
+	var sources []string
+	for source := range chains {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		if targets := chains[source]; len(targets) > 1 && prefer[source] == nil {
+			sort.Strings(targets)
+			return nil, fmt.Errorf("several translators for %s (%s), choose an order like %s:%s,%s",
+				source, strings.Join(targets, ", "), source, strings.Join(targets, ","), source)
+		}
+	}
+	return chains, nil
+}
+
+func printInit(shell string, add []string, remove []string, prefer map[string][]string) error {
+	chains, err := initChains(add, remove, prefer)
+	if err != nil {
+		return err
 	}
-	sort.Strings(names)
+
+	// Convert to sorted slice
+	var sources []string
+	for source := range chains {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
 
 	switch shell {
 	case "fish":
 		fmt.Println("# reflag shell init - add to your ~/.config/fish/config.fish")
 		fmt.Println()
-		for _, name := range names {
-			t := translator.GetByName(name)
-			fmt.Printf("functions -e %s 2>/dev/null\n", t.SourceTool())
-			fmt.Printf("function %s\n", t.SourceTool())
-			fmt.Printf("    eval (reflag %s %s $argv)\n", t.SourceTool(), t.TargetTool())
+		for _, source := range sources {
+			fmt.Printf("functions -e %s 2>/dev/null\n", source)
+			fmt.Printf("function %s\n", source)
+			fmt.Println("    set -l piped")
+			fmt.Println("    isatty stdout; or set piped --piped")
+			fmt.Printf("    eval (reflag $piped %s %s $argv)\n", source, strings.Join(chains[source], ","))
 			fmt.Println("end")
 			fmt.Println()
 		}
 	default: // bash, zsh
 		fmt.Println("# reflag shell init - add to your ~/.bashrc or ~/.zshrc")
 		fmt.Println()
-		for _, name := range names {
-			t := translator.GetByName(name)
-			fmt.Printf("unalias %s 2>/dev/null\n", t.SourceTool())
-			fmt.Printf("%s() {\n", t.SourceTool())
-			fmt.Printf("    eval \"$(reflag %s %s \"$@\")\"\n", t.SourceTool(), t.TargetTool())
+		for _, source := range sources {
+			fmt.Printf("unalias %s 2>/dev/null\n", source)
+			fmt.Printf("%s() {\n", source)
+			fmt.Println("    local piped=")
+			fmt.Println("    [ -t 1 ] || piped=--piped")
+			fmt.Printf("    eval \"$(reflag $piped %s %s \"$@\")\"\n", source, strings.Join(chains[source], ","))
 			fmt.Println("}")
 			fmt.Println()
 		}
 	}
+	return nil
 }
 
 func printUsage() {
@@ -158,25 +221,118 @@ func printUsage() {
 	fmt.Println("  echo 'reflag --init fish | source' >> ~/.config/fish/config.fish")
 	fmt.Println()
 	fmt.Println("Usage:")
-	fmt.Println("  reflag [--mode=MODE] <source> <target> [flags...]")
+	fmt.Println("  reflag [--mode=MODE] [--piped] <source> <target>[,target...] [flags...]")
 	fmt.Println("  reflag --list")
-	fmt.Println("  reflag --init [bash|zsh|fish] [+translator...] [-translator...]")
+	fmt.Println("  reflag --init [bash|zsh|fish] [+translator...] [-translator...] [source:target,...]")
 	fmt.Println("  reflag --version")
 	fmt.Println("  reflag --license")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  --mode=MODE    Set dialect mode (e.g., bsd or gnu for ls2eza)")
+	fmt.Println("  --mode=MODE    Set dialect mode (e.g., bsd or gnu for ls2eza and grep2rg, bsd or procps for ps2procs and top2procs)")
 	fmt.Println("                 Auto-detects from OS if not specified")
+	fmt.Println("  --piped        Output of the command is not a terminal")
+	fmt.Println("                 Set by the --init wrappers")
 	fmt.Println()
 	fmt.Println("Init modifiers:")
 	fmt.Println("  +translator    Add translator to defaults (e.g., +dig2doggo)")
 	fmt.Println("  -translator    Remove translator from defaults (e.g., -ls2eza)")
+	fmt.Println("  source:targets Try targets in order, the source itself being the original")
+	fmt.Println("                 command (e.g., ls:eza,lsd,ls)")
 	fmt.Println()
 	fmt.Println("Available translators:")
 	translator.PrintTable(os.Stdout)
 }
 
-func runTranslator(t translator.Translator, args []string, mode string) {
+// parseOptions consumes reflag's own leading options (--mode, --piped)
+// and returns the remaining arguments
+func parseOptions(args []string) (opts translator.Options, rest []string) {
+	for len(args) > 0 {
+		switch {
+		case strings.HasPrefix(args[0], "--mode="):
+			opts.Mode = strings.TrimPrefix(args[0], "--mode=")
+			args = args[1:]
+		case args[0] == "--mode" && len(args) > 1:
+			opts.Mode = args[1]
+			args = args[2:]
+		case args[0] == "--piped":
+			opts.Piped = true
+			args = args[1:]
+		default:
+			return opts, args
+		}
+	}
+	return opts, args
+}
+
+// environ returns the current environment as a map
+func environ() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, val, ok := strings.Cut(kv, "="); ok {
+			env[key] = val
+		}
+	}
+	return env
+}
+
+// buildCommand formats a translation result as a shell command line,
+// prefixed with any environment assignments
+func buildCommand(target string, result translator.Result) string {
+	var keys []string
+	for key := range result.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		parts = append(parts, key+"="+shellQuote(result.Env[key]))
+	}
+	parts = append(parts, target)
+	for _, arg := range result.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// fallbackCommand returns the original command, bypassing the shell function
+func fallbackCommand(source string, args []string) string {
+	return buildCommand("command", translator.Result{Args: append([]string{source}, args...)})
+}
+
+// selectTranslator picks the first target whose translator is registered and
+// whose tool is installed, trying them in order
+// A target equal to the source tool means falling back to the original command,
+// which is reported as a nil translator, as is a chain with nothing installed
+// A single target is used without checking that it's installed
+func selectTranslator(source string, targets []string, lookPath func(string) (string, error)) (translator.Translator, error) {
+	for _, target := range targets {
+		if target == source {
+			return nil, nil
+		}
+		t := translator.Get(source, target)
+		if t == nil {
+			var available []string
+			for _, t := range translator.ForSource(source) {
+				available = append(available, t.TargetTool())
+			}
+			if len(available) == 0 {
+				return nil, fmt.Errorf("no translator registered for %s to %s", source, target)
+			}
+			return nil, fmt.Errorf("no translator registered for %s to %s (available: %s)", source, target, strings.Join(available, ", "))
+		}
+		if len(targets) == 1 {
+			return t, nil
+		}
+		if _, err := lookPath(target); err == nil {
+			return t, nil
+		}
+	}
+	// Chains like ls:eza,lsd may leave out the original command
+	return nil, nil
+}
+
+func runTranslator(t translator.Translator, args []string, opts translator.Options) {
 	// Handle version flag
 	for _, arg := range args {
 		if arg == "-V" || arg == "--version" {
@@ -185,15 +341,18 @@ func runTranslator(t translator.Translator, args []string, mode string) {
 		}
 	}
 
-	translatedArgs := t.Translate(args, mode)
+	result := t.Translate(args, opts)
+	if result.Fallback {
+		fmt.Println(fallbackCommand(t.SourceTool(), args))
+		return
+	}
 
-	// Build and print the command
-	parts := make([]string, len(translatedArgs)+1)
-	parts[0] = t.TargetTool()
-	for i, arg := range translatedArgs {
-		parts[i+1] = shellQuote(arg)
+	// Warnings go to stderr, since the shell wrappers evaluate stdout
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s: %s\n", t.Name(), w)
 	}
-	fmt.Println(strings.Join(parts, " "))
+
+	fmt.Println(buildCommand(t.TargetTool(), result))
 }
 
 func main() {
@@ -219,35 +378,37 @@ func main() {
 		printUsage()
 		return
 	case "--init":
-		shell, add, remove := parseInitArgs(args[1:])
-		printInit(shell, add, remove)
+		shell, add, remove, prefer := parseInitArgs(args[1:])
+		if err := printInit(shell, add, remove, prefer); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	// Parse --mode flag if present
-	mode := ""
-	if strings.HasPrefix(args[0], "--mode=") {
-		mode = strings.TrimPrefix(args[0], "--mode=")
-		args = args[1:]
-	} else if args[0] == "--mode" && len(args) > 1 {
-		mode = args[1]
-		args = args[2:]
-	}
+	// Parse --mode and --piped flags if present
+	opts, args := parseOptions(args)
+	opts.Env = environ()
 
-	// Explicit mode: reflag [--mode=MODE] <source> <target> [flags...]
+	// Explicit mode: reflag [--mode=MODE] [--piped] <source> <target>[,target...] [flags...]
 	if len(args) < 2 {
 		fmt.Fprintln(os.Stderr, "error: expected <source> <target> arguments")
-		fmt.Fprintln(os.Stderr, "usage: reflag [--mode=MODE] <source> <target> [flags...]")
+		fmt.Fprintln(os.Stderr, "usage: reflag [--mode=MODE] [--piped] <source> <target>[,target...] [flags...]")
 		os.Exit(1)
 	}
 
-	source, target := args[0], args[1]
-	t := translator.Get(source, target)
-	if t == nil {
-		fmt.Fprintf(os.Stderr, "error: no translator registered for %s to %s\n", source, target)
+	source, targets := args[0], strings.Split(args[1], ",")
+	t, err := selectTranslator(source, targets, exec.LookPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		fmt.Fprintln(os.Stderr, "use 'reflag --list' to see available translators")
 		os.Exit(1)
 	}
 
-	runTranslator(t, args[2:], mode)
+	if t == nil {
+		fmt.Println(fallbackCommand(source, args[2:]))
+		return
+	}
+
+	runTranslator(t, args[2:], opts)
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"reflect"
 	"slices"
 	"testing"
 
@@ -23,6 +25,8 @@ func TestShellQuote(t *testing.T) {
 		{"with`backtick", "'with`backtick'"},
 		{"with\\backslash", "'with\\backslash'"},
 		{"with!exclaim", "'with!exclaim'"},
+		{"with;semicolon", "'with;semicolon'"},
+		{"*.go", "'*.go'"},
 	}
 
 	for _, tt := range tests {
@@ -35,6 +39,49 @@ func TestShellQuote(t *testing.T) {
 	}
 }
 
+func TestBuildCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   translator.Result
+		expected string
+	}{
+		{
+			name:     "args only",
+			result:   translator.Result{Args: []string{"-l", "my file"}},
+			expected: "eza -l 'my file'",
+		},
+		{
+			name:     "no args",
+			result:   translator.Result{},
+			expected: "eza",
+		},
+		{
+			name: "environment assignments",
+			result: translator.Result{
+				Args: []string{"-l"},
+				Env:  map[string]string{"EZA_COLORS": "di=1;34", "A": "1"},
+			},
+			expected: "A=1 EZA_COLORS='di=1;34' eza -l",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildCommand("eza", tt.result); got != tt.expected {
+				t.Errorf("buildCommand() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFallbackCommand(t *testing.T) {
+	got := fallbackCommand("ps", []string{"-N", "-o", "pid,comm=COMMAND NAME"})
+	expected := "command ps -N -o 'pid,comm=COMMAND NAME'"
+	if got != expected {
+		t.Errorf("fallbackCommand() = %q, want %q", got, expected)
+	}
+}
+
 func TestParseInitArgs(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -42,6 +89,7 @@ func TestParseInitArgs(t *testing.T) {
 		expectedShell  string
 		expectedAdd    []string
 		expectedRemove []string
+		expectedPrefer map[string][]string
 	}{
 		{
 			name:           "no args defaults to bash",
@@ -99,6 +147,14 @@ func TestParseInitArgs(t *testing.T) {
 			expectedAdd:    []string{"dig2doggo"},
 			expectedRemove: []string{"ls2eza"},
 		},
+		{
+			name:           "target preference",
+			args:           []string{"zsh", "+ls2lsd", "ls:lsd,eza,ls"},
+			expectedShell:  "zsh",
+			expectedAdd:    []string{"ls2lsd"},
+			expectedRemove: nil,
+			expectedPrefer: map[string][]string{"ls": {"lsd", "eza", "ls"}},
+		},
 		{
 			name:           "multiple shells takes last",
 			args:           []string{"bash", "fish", "zsh"},
@@ -110,7 +166,7 @@ func TestParseInitArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			shell, add, remove := parseInitArgs(tt.args)
+			shell, add, remove, prefer := parseInitArgs(tt.args)
 			if shell != tt.expectedShell {
 				t.Errorf("parseInitArgs(%v) shell = %q, want %q", tt.args, shell, tt.expectedShell)
 			}
@@ -120,6 +176,198 @@ func TestParseInitArgs(t *testing.T) {
 			if !slices.Equal(remove, tt.expectedRemove) {
 				t.Errorf("parseInitArgs(%v) remove = %v, want %v", tt.args, remove, tt.expectedRemove)
 			}
+			if !reflect.DeepEqual(prefer, tt.expectedPrefer) {
+				t.Errorf("parseInitArgs(%v) prefer = %v, want %v", tt.args, prefer, tt.expectedPrefer)
+			}
+		})
+	}
+}
+
+func TestInitChains(t *testing.T) {
+	tests := []struct {
+		name     string
+		add      []string
+		remove   []string
+		prefer   map[string][]string
+		source   string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "single default translator",
+			source:   "ls",
+			expected: []string{"eza"},
+		},
+		{
+			name:     "replaced translator",
+			add:      []string{"ls2lsd"},
+			remove:   []string{"ls2eza"},
+			source:   "ls",
+			expected: []string{"lsd"},
+		},
+		{
+			name:    "ambiguous translators",
+			add:     []string{"ls2lsd"},
+			wantErr: true,
+		},
+		{
+			name:     "preference resolves ambiguity",
+			add:      []string{"ls2lsd"},
+			prefer:   map[string][]string{"ls": {"lsd", "eza", "ls"}},
+			source:   "ls",
+			expected: []string{"lsd", "eza", "ls"},
+		},
+		{
+			name:     "preference enables translators",
+			prefer:   map[string][]string{"ls": {"lsd", "ls"}},
+			source:   "ls",
+			expected: []string{"lsd", "ls"},
+		},
+		{
+			name:    "preference with unknown target",
+			prefer:  map[string][]string{"ls": {"exa", "ls"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chains, err := initChains(tt.add, tt.remove, tt.prefer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("initChains() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !slices.Equal(chains[tt.source], tt.expected) {
+				t.Errorf("initChains() %s = %v, want %v", tt.source, chains[tt.source], tt.expected)
+			}
+		})
+	}
+}
+
+func TestSelectTranslator(t *testing.T) {
+	installed := func(tools ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			if slices.Contains(tools, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name      string
+		targets   []string
+		installed []string
+		expected  string // translator name, "" for the original command
+		wantErr   bool
+	}{
+		{
+			name:      "first installed target",
+			targets:   []string{"eza", "lsd", "ls"},
+			installed: []string{"eza", "lsd"},
+			expected:  "ls2eza",
+		},
+		{
+			name:      "skips missing target",
+			targets:   []string{"eza", "lsd", "ls"},
+			installed: []string{"lsd"},
+			expected:  "ls2lsd",
+		},
+		{
+			name:     "falls back to the original command",
+			targets:  []string{"eza", "lsd", "ls"},
+			expected: "",
+		},
+		{
+			name:     "nothing installed runs the original command",
+			targets:  []string{"eza", "lsd"},
+			expected: "",
+		},
+		{
+			name:     "single target isn't checked",
+			targets:  []string{"eza"},
+			expected: "ls2eza",
+		},
+		{
+			name:    "unknown target",
+			targets: []string{"exa", "ls"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := selectTranslator("ls", tt.targets, installed(tt.installed...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectTranslator(%v) error = %v, wantErr %v", tt.targets, err, tt.wantErr)
+			}
+			got := ""
+			if tr != nil {
+				got = tr.Name()
+			}
+			if got != tt.expected {
+				t.Errorf("selectTranslator(%v) = %q, want %q", tt.targets, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectedOpts translator.Options
+		expectedRest []string
+	}{
+		{
+			name:         "no options",
+			args:         []string{"ls", "eza", "-l"},
+			expectedOpts: translator.Options{},
+			expectedRest: []string{"ls", "eza", "-l"},
+		},
+		{
+			name:         "mode with equals",
+			args:         []string{"--mode=bsd", "ls", "eza"},
+			expectedOpts: translator.Options{Mode: "bsd"},
+			expectedRest: []string{"ls", "eza"},
+		},
+		{
+			name:         "mode separate",
+			args:         []string{"--mode", "gnu", "ls", "eza"},
+			expectedOpts: translator.Options{Mode: "gnu"},
+			expectedRest: []string{"ls", "eza"},
+		},
+		{
+			name:         "piped",
+			args:         []string{"--piped", "grep", "rg", "foo"},
+			expectedOpts: translator.Options{Piped: true},
+			expectedRest: []string{"grep", "rg", "foo"},
+		},
+		{
+			name:         "piped and mode in any order",
+			args:         []string{"--piped", "--mode=bsd", "ls", "eza"},
+			expectedOpts: translator.Options{Mode: "bsd", Piped: true},
+			expectedRest: []string{"ls", "eza"},
+		},
+		{
+			name:         "options after source are not consumed",
+			args:         []string{"grep", "rg", "--piped"},
+			expectedOpts: translator.Options{},
+			expectedRest: []string{"grep", "rg", "--piped"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, rest := parseOptions(tt.args)
+			if !reflect.DeepEqual(opts, tt.expectedOpts) {
+				t.Errorf("parseOptions(%v) opts = %+v, want %+v", tt.args, opts, tt.expectedOpts)
+			}
+			if !slices.Equal(rest, tt.expectedRest) {
+				t.Errorf("parseOptions(%v) rest = %v, want %v", tt.args, rest, tt.expectedRest)
+			}
 		})
 	}
 }
@@ -19,8 +19,8 @@ func (t *Translator) TargetTool() string  { return "bat" }
 func (t *Translator) IncludeInInit() bool { return true }
 
 // Translate converts cat arguments to bat arguments to make bat behave like cat
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args)
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translator.Result{Args: translateFlags(args)}
 }
 
 // Map of bat short flags to cat equivalents
@@ -3,6 +3,8 @@ package bat2cat
 import (
 	"reflect"
 	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
 )
 
 func TestTranslateFlags(t *testing.T) {
@@ -227,7 +229,7 @@ func TestTranslateMethod(t *testing.T) {
 	input := []string{"-n", "file.txt"}
 	expected := []string{"-p", "--paging=never", "--color=auto", "-n", "file.txt"}
 
-	result := tr.Translate(input, "")
+	result := tr.Translate(input, translator.Options{}).Args
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("Translate(%v, '') = %v, want %v", input, result, expected)
 	}
@@ -19,8 +19,8 @@ func (t *Translator) TargetTool() string  { return "duf" }
 func (t *Translator) IncludeInInit() bool { return true }
 
 // Translate converts du arguments to duf arguments
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args)
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translator.Result{Args: translateFlags(args)}
 }
 
 // Flags to ignore or that have no duf equivalent
@@ -3,6 +3,8 @@ package df2duf
 import (
 	"reflect"
 	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
 )
 
 func TestTranslateFlags(t *testing.T) {
@@ -218,7 +220,7 @@ func TestTranslator(t *testing.T) {
 	}
 
 	// Test Translate method
-	result := tr.Translate([]string{"-lh", "/tmp"}, "")
+	result := tr.Translate([]string{"-lh", "/tmp"}, translator.Options{}).Args
 	expected := []string{}
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("Translate(['-lh', '/tmp'], '') = %v, want %v", result, expected)
@@ -17,8 +17,8 @@ func (t *Translator) SourceTool() string  { return "dig" }
 func (t *Translator) TargetTool() string  { return "doggo" }
 func (t *Translator) IncludeInInit() bool { return true }
 
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args)
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translator.Result{Args: translateFlags(args)}
 }
 
 func translateFlags(args []string) []string {
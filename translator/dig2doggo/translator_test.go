@@ -3,6 +3,8 @@ package dig2doggo
 import (
 	"reflect"
 	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
 )
 
 func TestTranslate(t *testing.T) {
@@ -186,7 +188,7 @@ func TestTranslate(t *testing.T) {
 	tr := &Translator{}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tr.Translate(tt.args, "")
+			got := tr.Translate(tt.args, translator.Options{}).Args
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Translate() = %v, want %v", got, tt.want)
 			}
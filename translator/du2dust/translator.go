@@ -19,35 +19,58 @@ func (t *Translator) TargetTool() string  { return "dust" }
 func (t *Translator) IncludeInInit() bool { return true }
 
 // Translate converts du arguments to dust arguments
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args)
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translateFlags(args, opts.Piped)
+}
+
+// Options for plain output when dust's output goes to a pipe: full paths,
+// and no bars or colors
+var plainOutput = []string{"-p", "-b", "--no-colors"}
+
+// allLines is a dust line count that's large enough to show every entry
+const allLines = "1000000"
+
+// Options whose output only du can produce
+var fallbackFlags = map[string]bool{
+	"-0":           true, // null-terminated lines
+	"--null":       true,
+	"--time":       true, // modification times
+	"--time-style": true,
 }
 
 // Flags to ignore (dust handles automatically or no equivalent)
 var ignoredFlags = map[string]bool{
-	"-h":                 true, // dust is human-readable by default
-	"--human-readable":   true,
-	"-c":                 true, // dust shows total by default
-	"--total":            true,
 	"-P":                 true, // don't follow symlinks (dust default)
 	"--no-dereference":   true,
 	"-l":                 true, // count links
 	"--count-links":      true,
 	"-S":                 true, // separate dirs
 	"--separate-dirs":    true,
-	"--time":             true, // no equivalent
-	"--time-style":       true,
-	"-0":                 true, // null terminated
-	"--null":             true,
 	"-H":                 true, // dereference args
 	"--dereference-args": true,
 	"-D":                 true, // BSD/GNU dereference args
 }
 
-func translateFlags(args []string) []string {
+func translateFlags(args []string, piped bool) translator.Result {
 	var dustArgs []string
 	var paths []string
+	var depth string
+	allFiles := false
 	skipNext := false
+	// humanReadable reports whether the last size unit has suffixes
+	// Scripts parse du's plain counts otherwise, which dust can't print
+	humanReadable := false
+	total := false
+
+	// threshold translates du's size threshold, reporting false for a
+	// negative one (entries smaller than the size), which only du can show
+	threshold := func(val string) bool {
+		if strings.HasPrefix(val, "-") {
+			return false
+		}
+		dustArgs = append(dustArgs, "-z", val)
+		return true
+	}
 
 	for i, arg := range args {
 		if skipNext {
@@ -60,36 +83,72 @@ func translateFlags(args []string) []string {
 			if idx := strings.Index(arg, "="); idx != -1 {
 				opt := arg[:idx]
 				val := arg[idx+1:]
+				if fallbackFlags[opt] {
+					return translator.Result{Fallback: true}
+				}
 
 				switch opt {
 				case "--max-depth":
-					dustArgs = append(dustArgs, "-d", val)
+					depth = val
 				case "--exclude":
 					dustArgs = append(dustArgs, "-v", val)
 				case "--threshold":
-					dustArgs = append(dustArgs, "-z", val)
+					if !threshold(val) {
+						return translator.Result{Fallback: true}
+					}
 				case "--block-size":
 					// Try to map common block sizes
+					humanReadable = false
 					dustArgs = append(dustArgs, mapBlockSize(val)...)
 				}
 				continue
 			}
 
+			if fallbackFlags[arg] {
+				return translator.Result{Fallback: true}
+			}
+
 			// Handle standalone long options
 			switch arg {
+			case "--max-depth":
+				if i+1 < len(args) {
+					depth = args[i+1]
+					skipNext = true
+				}
+			case "--threshold":
+				if i+1 < len(args) {
+					if !threshold(args[i+1]) {
+						return translator.Result{Fallback: true}
+					}
+					skipNext = true
+				}
 			case "--summarize":
-				dustArgs = append(dustArgs, "-d", "0")
+				depth = "0"
 			case "--all":
-				dustArgs = append(dustArgs, "-F")
+				// dust lists files along with directories
+				allFiles = true
 			case "--dereference":
 				dustArgs = append(dustArgs, "-L")
 			case "--one-file-system":
 				dustArgs = append(dustArgs, "-x")
 			case "--apparent-size":
 				dustArgs = append(dustArgs, "-s")
+			case "--human-readable":
+				// dust is human-readable by default
+				humanReadable = true
+			case "--total":
+				total = true
+			case "--block-size":
+				if i+1 < len(args) {
+					humanReadable = false
+					dustArgs = append(dustArgs, mapBlockSize(args[i+1])...)
+					skipNext = true
+				}
 			case "--si":
+				humanReadable = true
 				dustArgs = append(dustArgs, "-o", "si")
 			case "--bytes":
+				humanReadable = false
 				dustArgs = append(dustArgs, "-o", "b")
 			case "--inodes":
 				dustArgs = append(dustArgs, "-f")
@@ -108,9 +167,9 @@ func translateFlags(args []string) []string {
 			for j, c := range flags {
 				switch c {
 				case 's': // summarize
-					dustArgs = append(dustArgs, "-d", "0")
-				case 'a': // all files
-					dustArgs = append(dustArgs, "-F")
+					depth = "0"
+				case 'a': // all files, which dust lists along with directories
+					allFiles = true
 				case 'd': // max depth
 					remaining := flags[j+1:]
 					var val string
@@ -121,32 +180,40 @@ func translateFlags(args []string) []string {
 						skipNext = true
 					}
 					if val != "" {
-						dustArgs = append(dustArgs, "-d", val)
+						depth = val
 					}
 					goto nextArg
 				case 'L': // follow symlinks
 					dustArgs = append(dustArgs, "-L")
 				case 'x': // one file system
 					dustArgs = append(dustArgs, "-x")
+				case 'h': // dust is human-readable by default
+					humanReadable = true
+				case 'c': // grand total
+					total = true
 				case 'b': // bytes (GNU)
+					humanReadable = false
 					dustArgs = append(dustArgs, "-o", "b")
 				case 'k': // kilobytes
+					humanReadable = false
 					dustArgs = append(dustArgs, "-o", "kb")
 				case 'm': // megabytes
+					humanReadable = false
 					dustArgs = append(dustArgs, "-o", "mb")
 				case 'g': // gigabytes (BSD)
+					humanReadable = false
 					dustArgs = append(dustArgs, "-o", "gb")
-				case 't': // threshold
+				case 't': // threshold, which may be negative
 					remaining := flags[j+1:]
 					var val string
 					if len(remaining) > 0 {
 						val = string(remaining)
-					} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+					} else if i+1 < len(args) {
 						val = args[i+1]
 						skipNext = true
 					}
-					if val != "" {
-						dustArgs = append(dustArgs, "-z", val)
+					if val != "" && !threshold(val) {
+						return translator.Result{Fallback: true}
 					}
 					goto nextArg
 				case 'I': // BSD exclude pattern
@@ -172,6 +239,7 @@ func translateFlags(args []string) []string {
 						skipNext = true
 					}
 					if val != "" {
+						humanReadable = false
 						dustArgs = append(dustArgs, mapBlockSize(val)...)
 					}
 					goto nextArg
@@ -180,7 +248,9 @@ func translateFlags(args []string) []string {
 						skipNext = true
 					}
 					goto nextArg
-				case 'h', 'c', 'P', 'l', 'S', 'H', 'D', '0':
+				case '0': // null-terminated lines
+					return translator.Result{Fallback: true}
+				case 'P', 'l', 'S', 'H', 'D':
 					// Ignored flags
 				default:
 					dustArgs = append(dustArgs, "-"+string(c))
@@ -194,11 +264,42 @@ func translateFlags(args []string) []string {
 		paths = append(paths, arg)
 	}
 
+	// Scripts read du's counts from a pipe, which dust can't print
+	if piped && !humanReadable {
+		return translator.Result{Fallback: true}
+	}
+
+	if depth != "" {
+		dustArgs = append(dustArgs, "-d", depth)
+	}
+
+	// du lists every directory down to the requested depth, and files too
+	// with -a, while dust only shows the biggest entries that fit the screen
+	// Without a depth or a pipe, dust's overview is kept
+	listAll := depth != "" || piped
+	if listAll && !allFiles && depth != "0" {
+		// Paths given with -s are listed even if they're files
+		dustArgs = append(dustArgs, "-D")
+	}
+
+	// Pipelines like du -sh * | sort -h need lines that can be sorted and parsed
+	if piped {
+		dustArgs = append(dustArgs, plainOutput...)
+		// du only prints a grand total with -c, so du -sh * | sort -h doesn't
+		// sort one in
+		if !total {
+			dustArgs = append(dustArgs, "--skip-total")
+		}
+	}
+	if listAll {
+		dustArgs = append(dustArgs, "-n", allLines)
+	}
+
 	// Build result
 	result := make([]string, 0, len(dustArgs)+len(paths))
 	result = append(result, dustArgs...)
 	result = append(result, paths...)
-	return result
+	return translator.Result{Args: result}
 }
 
 // mapBlockSize converts du block size to dust output format
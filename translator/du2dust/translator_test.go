@@ -32,29 +32,29 @@ func TestTranslateFlags(t *testing.T) {
 		{
 			name:     "summarize short",
 			input:    []string{"-s", "/tmp"},
-			expected: []string{"-d", "0", "/tmp"},
+			expected: []string{"-d", "0", "-n", "1000000", "/tmp"},
 		},
 		{
 			name:     "summarize long",
 			input:    []string{"--summarize", "/tmp"},
-			expected: []string{"-d", "0", "/tmp"},
+			expected: []string{"-d", "0", "-n", "1000000", "/tmp"},
 		},
 
 		// Depth
 		{
 			name:     "max depth short",
 			input:    []string{"-d", "2", "/tmp"},
-			expected: []string{"-d", "2", "/tmp"},
+			expected: []string{"-d", "2", "-D", "-n", "1000000", "/tmp"},
 		},
 		{
 			name:     "max depth long",
 			input:    []string{"--max-depth=3", "/tmp"},
-			expected: []string{"-d", "3", "/tmp"},
+			expected: []string{"-d", "3", "-D", "-n", "1000000", "/tmp"},
 		},
 		{
 			name:     "max depth attached",
 			input:    []string{"-d2", "/tmp"},
-			expected: []string{"-d", "2", "/tmp"},
+			expected: []string{"-d", "2", "-D", "-n", "1000000", "/tmp"},
 		},
 
 		// Human readable ignored (dust default)
@@ -73,12 +73,12 @@ func TestTranslateFlags(t *testing.T) {
 		{
 			name:     "all files",
 			input:    []string{"-a", "/tmp"},
-			expected: []string{"-F", "/tmp"},
+			expected: []string{"/tmp"},
 		},
 		{
 			name:     "all files long",
 			input:    []string{"--all", "/tmp"},
-			expected: []string{"-F", "/tmp"},
+			expected: []string{"/tmp"},
 		},
 
 		// Follow symlinks
@@ -167,12 +167,12 @@ func TestTranslateFlags(t *testing.T) {
 		{
 			name:     "combined sh",
 			input:    []string{"-sh", "/tmp"},
-			expected: []string{"-d", "0", "/tmp"},
+			expected: []string{"-d", "0", "-n", "1000000", "/tmp"},
 		},
 		{
 			name:     "typical usage",
 			input:    []string{"-shx", "/"},
-			expected: []string{"-d", "0", "-x", "/"},
+			expected: []string{"-x", "-d", "0", "-n", "1000000", "/"},
 		},
 
 		// Ignored flags
@@ -190,7 +190,7 @@ func TestTranslateFlags(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := translateFlags(tt.input)
+			result := translateFlags(tt.input, false).Args
 			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
 			}
@@ -198,6 +198,167 @@ func TestTranslateFlags(t *testing.T) {
 	}
 }
 
+func TestPipedAndFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		piped    bool
+		expected []string
+		fallback bool
+	}{
+		{
+			name:     "plain output when piped",
+			input:    []string{"-sh", "a", "b"},
+			piped:    true,
+			expected: []string{"-d", "0", "-p", "-b", "--no-colors", "--skip-total", "-n", "1000000", "a", "b"},
+		},
+		{
+			name:     "piped total",
+			input:    []string{"-ch", "a"},
+			piped:    true,
+			expected: []string{"-D", "-p", "-b", "--no-colors", "-n", "1000000", "a"},
+		},
+		{
+			name:     "piped total long",
+			input:    []string{"--total", "--si", "-s", "a"},
+			piped:    true,
+			expected: []string{"-o", "si", "-d", "0", "-p", "-b", "--no-colors", "-n", "1000000", "a"},
+		},
+		{
+			name:     "piped block counts",
+			input:    []string{"-s", "a"},
+			piped:    true,
+			fallback: true,
+		},
+		{
+			name:     "piped kilobyte counts",
+			input:    []string{"-sk", "a"},
+			piped:    true,
+			fallback: true,
+		},
+		{
+			name:     "piped block size counts",
+			input:    []string{"--block-size", "1M", "-s"},
+			piped:    true,
+			fallback: true,
+		},
+		{
+			name:     "piped bytes",
+			input:    []string{"-sb", "a"},
+			piped:    true,
+			fallback: true,
+		},
+		{
+			name:     "later units replace human-readable sizes",
+			input:    []string{"-hk", "a"},
+			piped:    true,
+			fallback: true,
+		},
+		{
+			name:     "terminal output",
+			input:    []string{"-h", "a"},
+			expected: []string{"a"},
+		},
+		{
+			name:     "plain output lists directories",
+			input:    []string{"-hd1"},
+			piped:    true,
+			expected: []string{"-d", "1", "-D", "-p", "-b", "--no-colors", "--skip-total", "-n", "1000000"},
+		},
+		{
+			name:     "plain output with all files",
+			input:    []string{"-ah"},
+			piped:    true,
+			expected: []string{"-p", "-b", "--no-colors", "--skip-total", "-n", "1000000"},
+		},
+		{
+			name:     "null-terminated lines",
+			input:    []string{"-0s", "a"},
+			piped:    true,
+			fallback: true,
+		},
+		{
+			name:     "null long",
+			input:    []string{"--null"},
+			fallback: true,
+		},
+		{
+			name:     "time",
+			input:    []string{"--time", "-s"},
+			fallback: true,
+		},
+		{
+			name:     "time word",
+			input:    []string{"--time=atime"},
+			fallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.piped)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v, %v) = %v, want %v", tt.input, tt.piped, result.Args, tt.expected)
+			}
+			if result.Fallback != tt.fallback {
+				t.Errorf("translateFlags(%v, %v) fallback = %v, want %v", tt.input, tt.piped, result.Fallback, tt.fallback)
+			}
+		})
+	}
+}
+
+func TestDepthAndThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+		fallback bool
+	}{
+		{
+			name:     "depth with all files",
+			input:    []string{"-a", "-d", "1"},
+			expected: []string{"-d", "1", "-n", "1000000"},
+		},
+		{
+			name:     "max depth with separate value",
+			input:    []string{"--max-depth", "1", "src"},
+			expected: []string{"-d", "1", "-D", "-n", "1000000", "src"},
+		},
+		{
+			name:     "threshold with separate value",
+			input:    []string{"--threshold", "1M"},
+			expected: []string{"-z", "1M"},
+		},
+		{
+			name:     "negative threshold",
+			input:    []string{"-t", "-1M"},
+			fallback: true,
+		},
+		{
+			name:     "negative threshold attached",
+			input:    []string{"-t-1M"},
+			fallback: true,
+		},
+		{
+			name:     "negative threshold long",
+			input:    []string{"--threshold=-1M"},
+			fallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, false)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if result.Fallback != tt.fallback {
+				t.Errorf("translateFlags(%v) fallback = %v, want %v", tt.input, result.Fallback, tt.fallback)
+			}
+		})
+	}
+}
+
 func TestTranslatorInterface(t *testing.T) {
 	tr := &Translator{}
 
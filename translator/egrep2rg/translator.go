@@ -0,0 +1,24 @@
+package egrep2rg
+
+import (
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/grep2rg"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the egrep to ripgrep flag translation
+// egrep is grep -E, whose syntax rg uses by default
+type Translator struct{}
+
+func (t *Translator) Name() string        { return "egrep2rg" }
+func (t *Translator) SourceTool() string  { return "egrep" }
+func (t *Translator) TargetTool() string  { return "rg" }
+func (t *Translator) IncludeInInit() bool { return true }
+
+// Translate converts egrep arguments to ripgrep arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return grep2rg.TranslateVariant(args, opts, grep2rg.Variant{Extended: true})
+}
@@ -0,0 +1,59 @@
+package egrep2rg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "alternation",
+			input:    []string{"foo|bar", "a.txt"},
+			expected: []string{"--max-depth=0", "foo|bar", "a.txt"},
+		},
+		{
+			name:     "redundant E ignored",
+			input:    []string{"-Ern", "foo+", "."},
+			expected: []string{"-n", "--no-ignore", "--hidden", "--binary", "foo+", "."},
+		},
+		{
+			name:     "backreference selects auto engine",
+			input:    []string{`(ab)\1`},
+			expected: []string{"--engine=auto", `(ab)\1`, "-"},
+		},
+	}
+
+	tr := &Translator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tr.Translate(tt.input, translator.Options{Mode: "gnu"}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Translate(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "egrep2rg" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "egrep2rg")
+	}
+	if tr.SourceTool() != "egrep" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "egrep")
+	}
+	if tr.TargetTool() != "rg" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "rg")
+	}
+	if !tr.IncludeInInit() {
+		t.Error("IncludeInInit() = false, want true")
+	}
+}
@@ -0,0 +1,24 @@
+package fgrep2rg
+
+import (
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/grep2rg"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the fgrep to ripgrep flag translation
+// fgrep is grep -F, so patterns are fixed strings
+type Translator struct{}
+
+func (t *Translator) Name() string        { return "fgrep2rg" }
+func (t *Translator) SourceTool() string  { return "fgrep" }
+func (t *Translator) TargetTool() string  { return "rg" }
+func (t *Translator) IncludeInInit() bool { return true }
+
+// Translate converts fgrep arguments to ripgrep arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return grep2rg.TranslateVariant(args, opts, grep2rg.Variant{FixedStrings: true})
+}
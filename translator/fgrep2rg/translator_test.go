@@ -0,0 +1,59 @@
+package fgrep2rg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "fixed strings by default",
+			input:    []string{"a.b", "a.txt"},
+			expected: []string{"-F", "--max-depth=0", "a.b", "a.txt"},
+		},
+		{
+			name:     "backreference stays literal",
+			input:    []string{`(ab)\1`},
+			expected: []string{"-F", `(ab)\1`, "-"},
+		},
+		{
+			name:     "recursive with flags",
+			input:    []string{"-rin", "TODO", "."},
+			expected: []string{"-F", "-i", "-n", "--no-ignore", "--hidden", "--binary", "TODO", "."},
+		},
+	}
+
+	tr := &Translator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tr.Translate(tt.input, translator.Options{Mode: "gnu"}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Translate(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "fgrep2rg" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "fgrep2rg")
+	}
+	if tr.SourceTool() != "fgrep" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "fgrep")
+	}
+	if tr.TargetTool() != "rg" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "rg")
+	}
+	if !tr.IncludeInInit() {
+		t.Error("IncludeInInit() = false, want true")
+	}
+}
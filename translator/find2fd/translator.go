@@ -20,8 +20,8 @@ func (t *Translator) TargetTool() string  { return "fd" }
 func (t *Translator) IncludeInInit() bool { return true }
 
 // Translate converts find arguments to fd arguments
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args)
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translator.Result{Args: translateFlags(args)}
 }
 
 // Expressions that take a value
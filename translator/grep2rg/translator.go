@@ -1,6 +1,10 @@
 package grep2rg
 
 import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/kluzzebass/reflag/translator"
@@ -19,8 +23,61 @@ func (t *Translator) TargetTool() string  { return "rg" }
 func (t *Translator) IncludeInInit() bool { return true }
 
 // Translate converts grep arguments to ripgrep arguments
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args)
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return TranslateVariant(args, opts, Variant{})
+}
+
+// Variant selects the defaults of a grep flavor such as egrep, fgrep or zgrep
+type Variant struct {
+	// FixedStrings treats patterns as fixed strings, like fgrep
+	FixedStrings bool
+
+	// Extended treats patterns as extended regular expressions, like egrep
+	Extended bool
+
+	// Decompress searches compressed files, like zgrep
+	Decompress bool
+}
+
+// TranslateVariant converts arguments of a grep variant to ripgrep arguments
+// It lets the egrep, fgrep and zgrep translators reuse grep2rg's flag handling
+func TranslateVariant(args []string, opts translator.Options, v Variant) translator.Result {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--help" {
+			// rg's help would describe rg's flags rather than grep's
+			return translator.Result{Fallback: true}
+		}
+	}
+	return translator.Result{Args: translateFlags(args, opts, v)}
+}
+
+// GrepMode determines which grep flavor to emulate
+type GrepMode int
+
+const (
+	ModeBSD GrepMode = iota
+	ModeGNU
+)
+
+// getGrepMode returns the grep compatibility mode based on mode string or OS detection
+func getGrepMode(mode string) GrepMode {
+	switch strings.ToLower(mode) {
+	case "bsd":
+		return ModeBSD
+	case "gnu":
+		return ModeGNU
+	}
+
+	// Auto-detect based on OS
+	switch runtime.GOOS {
+	case "darwin", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return ModeBSD
+	default:
+		return ModeGNU
+	}
 }
 
 // Flags that pass through unchanged (same in grep and rg)
@@ -29,19 +86,23 @@ var passthroughFlags = map[rune]bool{
 	'v': true, // invert match
 	'w': true, // word match
 	'x': true, // line match
-	'c': true, // count
 	'l': true, // files with matches
-	'L': true, // files without matches
 	'n': true, // line numbers
 	'H': true, // print filename
-	'h': true, // no filename
 	'o': true, // only matching
 	'q': true, // quiet
-	's': true, // suppress errors
 	'F': true, // fixed strings
 	'P': true, // perl regex
-	'a': true, // text (treat binary as text)
-	'U': true, // binary
+	'b': true, // byte offset
+}
+
+// Flags whose rg spelling differs from grep's
+var translatedFlags = map[rune][]string{
+	'L': {"--files-without-match"}, // rg's -L is --follow
+	'z': {"--null-data"},           // rg's -z is --search-zip
+	'Z': {"-0"},                    // NUL after file names
+	's': {"--no-messages"},         // rg's -s is --case-sensitive
+	'y': {"-i"},                    // obsolete synonym for -i
 }
 
 // Flags that take a value and pass through
@@ -56,61 +117,353 @@ var passthroughWithValue = map[rune]bool{
 
 // Flags to ignore (behavior is default in rg or not applicable)
 var ignoredFlags = map[rune]bool{
-	'r': true, // recursive (rg is recursive by default)
-	'R': true, // recursive
-	'E': true, // extended regexp (rg default)
-	'G': true, // basic regexp (no rg equivalent, close enough)
-	'I': true, // skip binary (rg default)
-	'b': true, // byte offset (rg has it but different)
 	'T': true, // initial tab
-	'd': true, // directory handling
-	'D': true, // device handling
-	'u': true, // unix byte offsets
+	'u': true, // unix byte offsets (obsolete, rg's -u is --unrestricted)
+	'U': true, // binary (only affects CRLF handling on DOS, rg's -U is --multiline)
+}
+
+// BSD grep flags that differ from GNU grep
+var bsdFlags = map[rune][]string{
+	'Z': {"-z"}, // decompress like zgrep (GNU: --null), rg's -z is --search-zip
+	'J': {"-z"}, // bzip2 decompress
+	'M': {"-z"}, // lzma decompress
+	'X': {"-z"}, // xz decompress
+}
+
+// BSD grep long options that differ from GNU grep
+var bsdLongFlagMap = map[string][]string{
+	"--decompress":    {"-z"},
+	"--bz2decompress": {"-z"},
+	"--lzma":          {"-z"},
+	"--xz":            {"-z"},
+	"--mmap":          {}, // no equivalent
+}
+
+// GNU grep long options without a value and their rg equivalents
+// An empty mapping means rg has no equivalent (or it's rg's default) and the option is dropped
+var longFlagMap = map[string][]string{
+	"--ignore-case":         {"--ignore-case"},
+	"--no-ignore-case":      {"--case-sensitive"},
+	"--invert-match":        {"--invert-match"},
+	"--word-regexp":         {"--word-regexp"},
+	"--line-regexp":         {"--line-regexp"},
+	"--line-number":         {"--line-number"},
+	"--files-with-matches":  {"--files-with-matches"},
+	"--files-without-match": {"--files-without-match"},
+	"--only-matching":       {"--only-matching"},
+	"--quiet":               {"--quiet"},
+	"--silent":              {"--quiet"},
+	"--no-messages":         {"--no-messages"},
+	"--null":                {"-0"},
+	"--null-data":           {"--null-data"},
+	"--byte-offset":         {"-b"},
+	"--line-buffered":       {"--line-buffered"},
+	"--no-group-separator":  {"--no-context-separator"},
+	"--binary":              {}, // only affects DOS line endings
+	"--initial-tab":         {}, // no equivalent
+	"--unix-byte-offsets":   {}, // obsolete no-op
+}
+
+// GNU grep long options that take a value and their rg equivalents
+// An empty mapping means rg has no equivalent and the option and its value are dropped
+var longValueMap = map[string]string{
+	"--max-count":       "-m",
+	"--after-context":   "-A",
+	"--before-context":  "-B",
+	"--context":         "-C",
+	"--group-separator": "--context-separator",
+	"--exclude-from":    "--ignore-file", // gitignore syntax, close to grep's globs
+	"--label":           "",              // no equivalent for naming stdin
+	"--devices":         "",              // no equivalent, rg skips devices while recursing
+}
+
+// Long options with special handling that take a value
+var specialValueOptions = map[string]bool{
+	"--include":      true,
+	"--exclude":      true,
+	"--exclude-dir":  true,
+	"--regexp":       true,
+	"--file":         true,
+	"--binary-files": true,
+	"--directories":  true,
+}
+
+// takesValue reports whether a long option requires a value
+// --color and --colour are excluded since their value is optional
+func takesValue(opt string) bool {
+	if specialValueOptions[opt] {
+		return true
+	}
+	_, ok := longValueMap[opt]
+	return ok
+}
+
+// translateColor converts grep's --color=WHEN values to rg's vocabulary
+func translateColor(when string) string {
+	switch when {
+	case "always", "yes", "force":
+		return "always"
+	case "never", "no", "none":
+		return "never"
+	default: // "", "auto", "tty", "if-tty"
+		return "auto"
+	}
+}
+
+// GREP_COLORS capabilities and the rg --colors type they map to
+var grepColorTypes = map[string]string{
+	"mt": "match",  // matched text in any line
+	"ms": "match",  // matched text in selected lines
+	"fn": "path",   // file names
+	"ln": "line",   // line numbers
+	"bn": "column", // byte offsets, closest to rg's column numbers
+}
+
+// rg --colors types in the order they are emitted
+var rgColorTypes = []string{"match", "path", "line", "column"}
+
+// ANSI color names in SGR order (30-37, 40-47, 90-97)
+var ansiColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// translateGrepColors converts GREP_COLORS (and the deprecated GREP_COLOR,
+// which only sets the match color) to rg --colors flags
+func translateGrepColors(grepColors, grepColor string) []string {
+	specs := make(map[string]string)
+	if grepColor != "" {
+		specs["match"] = grepColor
+	}
+	for _, field := range strings.Split(grepColors, ":") {
+		capability, sgr, ok := strings.Cut(field, "=")
+		if !ok {
+			// Boolean capabilities (rv, ne) have no rg equivalent
+			continue
+		}
+		if colorType, ok := grepColorTypes[capability]; ok {
+			specs[colorType] = sgr
+		}
+	}
+
+	var result []string
+	for _, colorType := range rgColorTypes {
+		sgr, ok := specs[colorType]
+		if !ok {
+			continue
+		}
+		// Clear rg's default before applying grep's color
+		result = append(result, "--colors="+colorType+":none")
+		for _, spec := range sgrToColorSpecs(sgr) {
+			result = append(result, "--colors="+colorType+":"+spec)
+		}
+	}
+	return result
 }
 
-// Long flags that pass through
-var longPassthrough = map[string]bool{
-	"--color":               true,
-	"--colour":              true,
-	"--line-number":         true,
-	"--with-filename":       true,
-	"--no-filename":         true,
-	"--count":               true,
-	"--files-with-matches":  true,
-	"--files-without-match": true,
-	"--only-matching":       true,
-	"--quiet":               true,
-	"--silent":              true,
-	"--invert-match":        true,
-	"--word-regexp":         true,
-	"--line-regexp":         true,
-	"--fixed-strings":       true,
-	"--perl-regexp":         true,
-	"--text":                true,
-	"--binary":              true,
-	"--max-count":           true,
-	"--after-context":       true,
-	"--before-context":      true,
-	"--context":             true,
+// sgrToColorSpecs converts an SGR sequence like "01;31" to rg color specs
+// like "style:bold" and "fg:red"
+func sgrToColorSpecs(sgr string) []string {
+	var params []int
+	for _, p := range strings.Split(sgr, ";") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			continue
+		}
+		params = append(params, n)
+	}
+
+	var specs []string
+	for i := 0; i < len(params); i++ {
+		switch n := params[i]; {
+		case n == 1:
+			specs = append(specs, "style:bold")
+		case n == 3:
+			specs = append(specs, "style:italic")
+		case n == 4:
+			specs = append(specs, "style:underline")
+		case n == 22:
+			specs = append(specs, "style:nobold")
+		case n == 23:
+			specs = append(specs, "style:noitalic")
+		case n == 24:
+			specs = append(specs, "style:nounderline")
+		case n >= 30 && n <= 37:
+			specs = append(specs, "fg:"+ansiColorNames[n-30])
+		case n >= 40 && n <= 47:
+			specs = append(specs, "bg:"+ansiColorNames[n-40])
+		case n >= 90 && n <= 97:
+			specs = append(specs, "fg:"+ansiColorNames[n-90], "style:intense")
+		case n >= 100 && n <= 107:
+			specs = append(specs, "bg:"+ansiColorNames[n-100])
+		case n == 38 || n == 48:
+			// Extended colors: 38;5;N (256 colors) or 38;2;R;G;B (truecolor)
+			layer := "fg:"
+			if n == 48 {
+				layer = "bg:"
+			}
+			if i+2 < len(params) && params[i+1] == 5 {
+				specs = append(specs, layer+strconv.Itoa(params[i+2]))
+				i += 2
+			} else if i+4 < len(params) && params[i+1] == 2 {
+				specs = append(specs, layer+fmt.Sprintf("0x%02x,0x%02x,0x%02x", params[i+2], params[i+3], params[i+4]))
+				i += 4
+			}
+		}
+	}
+	return specs
 }
 
-// Long flags to ignore
-var longIgnored = map[string]bool{
-	"--recursive":             true,
-	"--dereference-recursive": true,
-	"--extended-regexp":       true,
-	"--basic-regexp":          true,
-	"--binary-files":          true,
-	"--directories":           true,
-	"--devices":               true,
-	"--no-messages":           true,
+// PCRE2-only constructs that rg's default regex engine rejects
+var pcre2Constructs = []string{
+	"(?=",  // lookahead
+	"(?!",  // negative lookahead
+	"(?<=", // lookbehind
+	"(?<!", // negative lookbehind
+	"(?>",  // atomic group
+	"(?P=", // named backreference (Python syntax)
+	"(?|",  // branch reset
 }
 
-func translateFlags(args []string) []string {
+// needsPCRE2 reports whether a pattern uses backreferences or lookaround,
+// which only rg's PCRE2 engine supports
+func needsPCRE2(pattern string) bool {
+	for _, construct := range pcre2Constructs {
+		if strings.Contains(pattern, construct) {
+			return true
+		}
+	}
+
+	for i := 0; i < len(pattern)-1; i++ {
+		if pattern[i] != '\\' {
+			continue
+		}
+		switch next := pattern[i+1]; {
+		case next >= '1' && next <= '9':
+			return true // numbered backreference
+		case next == 'k' || next == 'g' || next == 'K':
+			return true // named backreference, \g{N} or \K match reset
+		}
+		// Skip the escaped character so \\1 is not mistaken for \1
+		i++
+	}
+	return false
+}
+
+// basicToExtended converts a POSIX basic regexp to the extended syntax rg uses
+// In a BRE, \( \) \{ \} \| \+ and \? are operators and the bare characters
+// are literals; in an ERE it's the other way around. Bracket expressions and
+// other escapes such as backreferences are kept as they are
+func basicToExtended(pattern string) string {
+	var b strings.Builder
+	// A star is a literal where there's nothing to repeat
+	start := true
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		atStart := start
+		start = false
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			if strings.IndexByte("(){}|+?", pattern[i]) != -1 {
+				b.WriteByte(pattern[i])
+				start = pattern[i] == '(' || pattern[i] == '|'
+			} else {
+				b.WriteByte(c)
+				b.WriteByte(pattern[i])
+			}
+		case strings.IndexByte("(){}|+?", c) != -1:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '*' && atStart:
+			b.WriteString(`\*`)
+		case c == '^' && atStart:
+			b.WriteByte(c)
+			start = true
+		case c == '[':
+			end := bracketEnd(pattern, i)
+			b.WriteString(pattern[i:end])
+			i = end - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// bracketEnd returns the index just past the bracket expression starting at
+// start, or the end of the pattern if it isn't closed
+func bracketEnd(pattern string, start int) int {
+	i := start + 1
+	if i < len(pattern) && pattern[i] == '^' {
+		i++
+	}
+	if i < len(pattern) && pattern[i] == ']' {
+		// A leading ] is a literal
+		i++
+	}
+	for i < len(pattern) {
+		switch {
+		case pattern[i] == ']':
+			return i + 1
+		case pattern[i] == '[' && i+1 < len(pattern) && strings.IndexByte(":.=", pattern[i+1]) != -1:
+			// Character classes such as [:digit:] end with :]
+			if end := strings.Index(pattern[i+2:], string(pattern[i+1])+"]"); end != -1 {
+				i += end + 4
+				continue
+			}
+		}
+		i++
+	}
+	return len(pattern)
+}
+
+// patternsNeedPCRE2 checks inline patterns and the contents of pattern files
+func patternsNeedPCRE2(patterns []string, patternFiles []string) bool {
+	for _, pat := range patterns {
+		if needsPCRE2(pat) {
+			return true
+		}
+	}
+	for _, file := range patternFiles {
+		if file == "-" {
+			// Patterns come from stdin and can't be inspected
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if needsPCRE2(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func translateFlags(args []string, opts translator.Options, variant Variant) []string {
+	mode := getGrepMode(opts.Mode)
 	var rgArgs []string
 	var patterns []string
+	var patternFiles []string
 	var paths []string
 	skipNext := false
+	fixedStrings := false
+	perlRegexp := false
+	extended := variant.Extended
+	recursive := false
+	followSymlinks := false
+	withFilename := false
+	noFilename := false
+	count := false
+	binaryFiles := "binary"
+
+	if variant.FixedStrings {
+		fixedStrings = true
+		rgArgs = append(rgArgs, "-F")
+	}
+	if variant.Decompress {
+		rgArgs = append(rgArgs, "-z")
+	}
 
 	for i, arg := range args {
 		if skipNext {
@@ -119,93 +472,94 @@ func translateFlags(args []string) []string {
 		}
 
 		if arg == "--" {
-			// Everything after -- is paths
-			paths = append(paths, args[i+1:]...)
+			// Everything after -- is paths, after the pattern if there's
+			// no -e or -f
+			rest := args[i+1:]
+			if len(patterns) == 0 && len(patternFiles) == 0 && len(rest) > 0 {
+				patterns = append(patterns, rest[0])
+				rest = rest[1:]
+			}
+			paths = append(paths, rest...)
 			break
 		}
 
 		if strings.HasPrefix(arg, "--") {
-			// Handle --option=value format
-			if idx := strings.Index(arg, "="); idx != -1 {
-				opt := arg[:idx]
-				val := arg[idx+1:]
-
-				switch opt {
-				case "--include":
-					rgArgs = append(rgArgs, "-g", val)
-				case "--exclude":
-					rgArgs = append(rgArgs, "-g", "!"+val)
-				case "--exclude-dir":
-					// Ensure directory pattern
-					if !strings.HasSuffix(val, "/") {
-						val = val + "/"
-					}
-					rgArgs = append(rgArgs, "-g", "!"+val)
-				case "--color", "--colour":
-					rgArgs = append(rgArgs, arg)
-				case "--regexp":
-					patterns = append(patterns, val)
-				case "--file":
-					rgArgs = append(rgArgs, "-f", val)
-				case "--max-count":
-					rgArgs = append(rgArgs, "-m", val)
-				case "--after-context":
-					rgArgs = append(rgArgs, "-A", val)
-				case "--before-context":
-					rgArgs = append(rgArgs, "-B", val)
-				case "--context":
-					rgArgs = append(rgArgs, "-C", val)
-				case "--label":
-					rgArgs = append(rgArgs, arg)
-				default:
-					if longPassthrough[opt] {
-						rgArgs = append(rgArgs, arg)
-					}
-					// Ignore unknown long options with values
-				}
-				continue
+			opt, val, hasValue := strings.Cut(arg, "=")
+
+			// Options that require a value take the next argument if none is attached
+			if !hasValue && takesValue(opt) && i+1 < len(args) {
+				val = args[i+1]
+				hasValue = true
+				skipNext = true
 			}
 
-			// Handle --option format
-			switch arg {
-			case "--null", "--null-data":
-				rgArgs = append(rgArgs, "-0")
-			case "--include", "--exclude", "--exclude-dir":
-				// These need a value
-				if i+1 < len(args) {
-					val := args[i+1]
-					skipNext = true
-					switch arg {
-					case "--include":
-						rgArgs = append(rgArgs, "-g", val)
-					case "--exclude":
-						rgArgs = append(rgArgs, "-g", "!"+val)
-					case "--exclude-dir":
-						if !strings.HasSuffix(val, "/") {
-							val = val + "/"
-						}
-						rgArgs = append(rgArgs, "-g", "!"+val)
-					}
+			switch opt {
+			case "--include":
+				rgArgs = append(rgArgs, "-g", val)
+			case "--exclude":
+				rgArgs = append(rgArgs, "-g", "!"+val)
+			case "--exclude-dir":
+				// Ensure directory pattern
+				if !strings.HasSuffix(val, "/") {
+					val = val + "/"
 				}
+				rgArgs = append(rgArgs, "-g", "!"+val)
 			case "--regexp":
-				if i+1 < len(args) {
-					patterns = append(patterns, args[i+1])
-					skipNext = true
-				}
+				patterns = append(patterns, val)
 			case "--file":
-				if i+1 < len(args) {
-					rgArgs = append(rgArgs, "-f", args[i+1])
-					skipNext = true
+				rgArgs = append(rgArgs, "-f", val)
+				patternFiles = append(patternFiles, val)
+			case "--color", "--colour":
+				// grep's WHEN is optional, rg requires it
+				rgArgs = append(rgArgs, "--color="+translateColor(val))
+			case "--binary-files":
+				binaryFiles = val
+			case "--directories":
+				if val == "recurse" {
+					recursive = true
+				}
+			case "--count":
+				count = true
+				rgArgs = append(rgArgs, "-c")
+			case "--binary":
+				if mode == ModeBSD {
+					// BSD: search binary files but don't print them
+					binaryFiles = "binary"
 				}
+			case "--dereference-recursive":
+				recursive = true
+				followSymlinks = mode == ModeGNU
+			case "--text":
+				binaryFiles = "text"
+			case "--with-filename":
+				withFilename = true
+				rgArgs = append(rgArgs, arg)
+			case "--no-filename":
+				noFilename = true
+				rgArgs = append(rgArgs, arg)
+			case "--recursive":
+				recursive = true
+			case "--fixed-strings":
+				fixedStrings = true
+				rgArgs = append(rgArgs, arg)
+			case "--perl-regexp":
+				perlRegexp = true
+				rgArgs = append(rgArgs, arg)
+			case "--extended-regexp":
+				extended = true
+			case "--basic-regexp":
+				extended = false
 			default:
-				if longPassthrough[arg] {
-					rgArgs = append(rgArgs, arg)
-				} else if longIgnored[arg] {
-					// Skip
-				} else {
-					// Pass through unknown
-					rgArgs = append(rgArgs, arg)
+				if mapped, ok := bsdLongFlagMap[opt]; ok && mode == ModeBSD {
+					rgArgs = append(rgArgs, mapped...)
+				} else if mapped, ok := longValueMap[opt]; ok {
+					if mapped != "" && hasValue {
+						rgArgs = append(rgArgs, mapped, val)
+					}
+				} else if mapped, ok := longFlagMap[opt]; ok {
+					rgArgs = append(rgArgs, mapped...)
 				}
+				// Unknown long options are dropped since rg would reject them
 			}
 			continue
 		}
@@ -213,8 +567,49 @@ func translateFlags(args []string) []string {
 		if strings.HasPrefix(arg, "-") && len(arg) > 1 && arg[1] != '-' {
 			// Short flags
 			flags := arg[1:]
+			skipDigits := 0
 			for j, c := range flags {
+				if skipDigits > 0 {
+					skipDigits--
+					continue
+				}
+
+				if c >= '0' && c <= '9' {
+					// -NUM is the same as --context=NUM
+					num := flags[j:]
+					if end := strings.IndexFunc(num, func(r rune) bool { return r < '0' || r > '9' }); end != -1 {
+						num = num[:end]
+					}
+					skipDigits = len(num) - 1
+					rgArgs = append(rgArgs, "-C", num)
+					continue
+				}
+
+				if c == 'd' || c == 'D' {
+					// Directory and device actions take a value
+					remaining := flags[j+1:]
+					var action string
+					if len(remaining) > 0 {
+						action = remaining
+					} else if i+1 < len(args) {
+						action = args[i+1]
+						skipNext = true
+					}
+					if c == 'd' && action == "recurse" {
+						recursive = true
+					}
+					break
+				}
+
 				if passthroughFlags[c] {
+					switch c {
+					case 'H':
+						withFilename = true
+					case 'F':
+						fixedStrings = true
+					case 'P':
+						perlRegexp = true
+					}
 					rgArgs = append(rgArgs, "-"+string(c))
 					continue
 				}
@@ -237,13 +632,75 @@ func translateFlags(args []string) []string {
 					if c == 'e' {
 						patterns = append(patterns, val)
 					} else {
+						if c == 'f' {
+							patternFiles = append(patternFiles, val)
+						}
 						rgArgs = append(rgArgs, "-"+string(c), val)
 					}
 					break
 				}
 
-				if c == 'Z' {
-					rgArgs = append(rgArgs, "-0")
+				if mode == ModeBSD {
+					if mapped, ok := bsdFlags[c]; ok {
+						rgArgs = append(rgArgs, mapped...)
+						continue
+					}
+					switch c {
+					case 'R':
+						// BSD -R is the same as -r, symlinks are controlled by -S/-O/-p
+						recursive = true
+						continue
+					case 'S':
+						// Follow all symlinks when recursing
+						followSymlinks = true
+						continue
+					case 'O', 'p':
+						// -O follows only command line symlinks and -p none, like rg
+						followSymlinks = false
+						continue
+					case 'U':
+						// Search binary files but don't print them
+						binaryFiles = "binary"
+						continue
+					}
+				}
+
+				if mapped, ok := translatedFlags[c]; ok {
+					rgArgs = append(rgArgs, mapped...)
+					continue
+				}
+
+				switch c {
+				case 'c':
+					count = true
+					rgArgs = append(rgArgs, "-c")
+					continue
+				case 'a':
+					binaryFiles = "text"
+					continue
+				case 'I':
+					binaryFiles = "without-match"
+					continue
+				}
+
+				if c == 'h' {
+					// rg's -h is --help
+					noFilename = true
+					rgArgs = append(rgArgs, "--no-filename")
+					continue
+				}
+
+				if c == 'r' || c == 'R' {
+					recursive = true
+					if c == 'R' {
+						followSymlinks = true
+					}
+					continue
+				}
+
+				if c == 'E' || c == 'G' {
+					// rg's syntax is ERE, basic regexps are converted below
+					extended = c == 'E'
 					continue
 				}
 
@@ -258,14 +715,76 @@ func translateFlags(args []string) []string {
 		}
 
 		// Non-flag argument
-		if len(patterns) == 0 && !strings.HasPrefix(arg, "-") {
-			// First non-flag is the pattern (if no -e was used)
+		if len(patterns) == 0 && len(patternFiles) == 0 && !strings.HasPrefix(arg, "-") {
+			// First non-flag is the pattern (if no -e or -f was used)
 			patterns = append(patterns, arg)
 		} else {
 			paths = append(paths, arg)
 		}
 	}
 
+	if recursive {
+		// grep -r searches everything; rg skips hidden and gitignored files
+		rgArgs = append(rgArgs, "--no-ignore", "--hidden")
+		if followSymlinks {
+			// -R follows all symlinks, -r only those named on the command line
+			rgArgs = append(rgArgs, "-L")
+		}
+	} else if len(paths) == 0 && (len(patterns) > 0 || len(patternFiles) > 0) {
+		// Without -r and without files grep reads stdin, rg would search cwd
+		paths = append(paths, "-")
+	} else if len(paths) > 0 {
+		// Without -r grep doesn't descend into directories
+		rgArgs = append(rgArgs, "--max-depth=0")
+	}
+
+	if count {
+		// grep -c prints a count for every file, including zero
+		rgArgs = append(rgArgs, "--include-zero")
+	}
+
+	switch binaryFiles {
+	case "text":
+		rgArgs = append(rgArgs, "-a")
+	case "binary":
+		if recursive {
+			// grep reports matches in binary files; rg skips them while
+			// recursing, but searches explicitly named files like grep
+			rgArgs = append(rgArgs, "--binary")
+		}
+	case "without-match":
+		// rg already skips binary files while recursing
+	}
+
+	if opts.Piped {
+		// Match grep's file:line:text layout for pipelines: no headings,
+		// no user config, and filenames only where grep would print them
+		rgArgs = append(rgArgs, "--no-config", "--no-heading")
+		if !withFilename && !noFilename {
+			if recursive || len(paths) > 1 {
+				rgArgs = append(rgArgs, "--with-filename")
+			} else {
+				rgArgs = append(rgArgs, "--no-filename")
+			}
+		}
+	}
+
+	rgArgs = append(rgArgs, translateGrepColors(opts.Getenv("GREP_COLORS"), opts.Getenv("GREP_COLOR"))...)
+
+	// grep defaults to basic regexps, where \( and ( swap meanings
+	// Pattern files are passed on as they are
+	if !fixedStrings && !perlRegexp && !extended {
+		for i, pat := range patterns {
+			patterns[i] = basicToExtended(pat)
+		}
+	}
+
+	// rg's default engine rejects backreferences and lookaround, so let rg
+	// switch to PCRE2 when needed and keep the faster engine otherwise
+	if !fixedStrings && !perlRegexp && patternsNeedPCRE2(patterns, patternFiles) {
+		rgArgs = append(rgArgs, "--engine=auto")
+	}
+
 	// Build final command - ensure we return empty slice not nil
 	result := make([]string, 0)
 	result = append(result, rgArgs...)
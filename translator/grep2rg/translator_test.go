@@ -1,8 +1,12 @@
 package grep2rg
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
 )
 
 func TestTranslateFlags(t *testing.T) {
@@ -15,223 +19,553 @@ func TestTranslateFlags(t *testing.T) {
 		{
 			name:     "simple pattern",
 			input:    []string{"pattern"},
-			expected: []string{"pattern"},
+			expected: []string{"pattern", "-"},
 		},
 		{
 			name:     "pattern with path",
 			input:    []string{"pattern", "file.txt"},
-			expected: []string{"pattern", "file.txt"},
+			expected: []string{"--max-depth=0", "pattern", "file.txt"},
 		},
 		{
 			name:     "pattern with multiple paths",
 			input:    []string{"pattern", "file1.txt", "file2.txt"},
-			expected: []string{"pattern", "file1.txt", "file2.txt"},
+			expected: []string{"--max-depth=0", "pattern", "file1.txt", "file2.txt"},
 		},
 
 		// Passthrough flags
 		{
 			name:     "case insensitive",
 			input:    []string{"-i", "pattern"},
-			expected: []string{"-i", "pattern"},
+			expected: []string{"-i", "pattern", "-"},
 		},
 		{
 			name:     "invert match",
 			input:    []string{"-v", "pattern"},
-			expected: []string{"-v", "pattern"},
+			expected: []string{"-v", "pattern", "-"},
 		},
 		{
 			name:     "word match",
 			input:    []string{"-w", "pattern"},
-			expected: []string{"-w", "pattern"},
+			expected: []string{"-w", "pattern", "-"},
 		},
 		{
 			name:     "line match",
 			input:    []string{"-x", "pattern"},
-			expected: []string{"-x", "pattern"},
+			expected: []string{"-x", "pattern", "-"},
 		},
 		{
 			name:     "count",
 			input:    []string{"-c", "pattern"},
-			expected: []string{"-c", "pattern"},
+			expected: []string{"-c", "--include-zero", "pattern", "-"},
 		},
 		{
 			name:     "files with matches",
 			input:    []string{"-l", "pattern"},
-			expected: []string{"-l", "pattern"},
+			expected: []string{"-l", "pattern", "-"},
 		},
 		{
 			name:     "line numbers",
 			input:    []string{"-n", "pattern"},
-			expected: []string{"-n", "pattern"},
+			expected: []string{"-n", "pattern", "-"},
 		},
 		{
 			name:     "combined passthrough",
 			input:    []string{"-inl", "pattern"},
-			expected: []string{"-i", "-n", "-l", "pattern"},
+			expected: []string{"-i", "-n", "-l", "pattern", "-"},
 		},
 
-		// Ignored flags (recursive, etc.)
+		// Recursion
 		{
-			name:     "recursive ignored",
+			name:     "recursive searches hidden and ignored files",
 			input:    []string{"-r", "pattern", "."},
-			expected: []string{"pattern", "."},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern", "."},
 		},
 		{
-			name:     "recursive R ignored",
+			name:     "dereference recursive follows symlinks",
 			input:    []string{"-R", "pattern", "."},
-			expected: []string{"pattern", "."},
+			expected: []string{"--no-ignore", "--hidden", "-L", "--binary", "pattern", "."},
 		},
 		{
 			name:     "extended regexp ignored",
 			input:    []string{"-E", "pattern"},
-			expected: []string{"pattern"},
+			expected: []string{"pattern", "-"},
 		},
 		{
-			name:     "combined with ignored",
+			name:     "combined with recursive",
 			input:    []string{"-rni", "pattern", "."},
-			expected: []string{"-n", "-i", "pattern", "."},
+			expected: []string{"-n", "-i", "--no-ignore", "--hidden", "--binary", "pattern", "."},
 		},
 
 		// Context flags
 		{
 			name:     "after context",
 			input:    []string{"-A", "3", "pattern"},
-			expected: []string{"-A", "3", "pattern"},
+			expected: []string{"-A", "3", "pattern", "-"},
 		},
 		{
 			name:     "before context",
 			input:    []string{"-B", "3", "pattern"},
-			expected: []string{"-B", "3", "pattern"},
+			expected: []string{"-B", "3", "pattern", "-"},
 		},
 		{
 			name:     "context",
 			input:    []string{"-C", "3", "pattern"},
-			expected: []string{"-C", "3", "pattern"},
+			expected: []string{"-C", "3", "pattern", "-"},
 		},
 		{
 			name:     "context attached",
 			input:    []string{"-A3", "pattern"},
-			expected: []string{"-A", "3", "pattern"},
+			expected: []string{"-A", "3", "pattern", "-"},
 		},
 
 		// Include/exclude
 		{
 			name:     "include pattern",
 			input:    []string{"--include=*.go", "pattern"},
-			expected: []string{"-g", "*.go", "pattern"},
+			expected: []string{"-g", "*.go", "pattern", "-"},
 		},
 		{
 			name:     "exclude pattern",
 			input:    []string{"--exclude=*.txt", "pattern"},
-			expected: []string{"-g", "!*.txt", "pattern"},
+			expected: []string{"-g", "!*.txt", "pattern", "-"},
 		},
 		{
 			name:     "exclude dir",
 			input:    []string{"--exclude-dir=vendor", "pattern"},
-			expected: []string{"-g", "!vendor/", "pattern"},
+			expected: []string{"-g", "!vendor/", "pattern", "-"},
 		},
 		{
 			name:     "exclude dir with slash",
 			input:    []string{"--exclude-dir=vendor/", "pattern"},
-			expected: []string{"-g", "!vendor/", "pattern"},
+			expected: []string{"-g", "!vendor/", "pattern", "-"},
 		},
 		{
 			name:     "include separate",
 			input:    []string{"--include", "*.go", "pattern"},
-			expected: []string{"-g", "*.go", "pattern"},
+			expected: []string{"-g", "*.go", "pattern", "-"},
 		},
 
 		// Multiple patterns with -e
 		{
 			name:     "single -e pattern",
 			input:    []string{"-e", "pattern"},
-			expected: []string{"pattern"},
+			expected: []string{"pattern", "-"},
 		},
 		{
 			name:     "multiple -e patterns",
 			input:    []string{"-e", "foo", "-e", "bar"},
-			expected: []string{"-e", "foo", "-e", "bar"},
+			expected: []string{"-e", "foo", "-e", "bar", "-"},
 		},
 		{
 			name:     "attached -e pattern",
 			input:    []string{"-epattern"},
-			expected: []string{"pattern"},
+			expected: []string{"pattern", "-"},
+		},
+
+		// Filename flags
+		{
+			name:     "no filename is not rg help",
+			input:    []string{"-h", "pattern", "a.txt", "b.txt"},
+			expected: []string{"--no-filename", "--max-depth=0", "pattern", "a.txt", "b.txt"},
+		},
+
+		// Count and list
+		{
+			name:     "count long includes zero",
+			input:    []string{"--count", "pattern", "a.txt", "b.txt"},
+			expected: []string{"-c", "--max-depth=0", "--include-zero", "pattern", "a.txt", "b.txt"},
+		},
+		{
+			name:     "recursive count",
+			input:    []string{"-rc", "pattern"},
+			expected: []string{"-c", "--no-ignore", "--hidden", "--include-zero", "--binary", "pattern"},
+		},
+		{
+			name:     "files without match is not follow",
+			input:    []string{"-L", "pattern", "a.txt"},
+			expected: []string{"--files-without-match", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "files without match long",
+			input:    []string{"--files-without-match", "pattern", "a.txt"},
+			expected: []string{"--files-without-match", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "files with matches recursive",
+			input:    []string{"-rl", "pattern"},
+			expected: []string{"-l", "--no-ignore", "--hidden", "--binary", "pattern"},
 		},
 
 		// Null separator
 		{
 			name:     "null short",
 			input:    []string{"-Z", "pattern"},
-			expected: []string{"-0", "pattern"},
+			expected: []string{"-0", "pattern", "-"},
 		},
 		{
 			name:     "null long",
 			input:    []string{"--null", "pattern"},
-			expected: []string{"-0", "pattern"},
+			expected: []string{"-0", "pattern", "-"},
+		},
+		{
+			name:     "null data short is not search zip",
+			input:    []string{"-z", "pattern"},
+			expected: []string{"--null-data", "pattern", "-"},
+		},
+		{
+			name:     "null data long",
+			input:    []string{"--null-data", "pattern"},
+			expected: []string{"--null-data", "pattern", "-"},
+		},
+		{
+			name:     "list with null",
+			input:    []string{"-rlZ", "pattern"},
+			expected: []string{"-l", "-0", "--no-ignore", "--hidden", "--binary", "pattern"},
+		},
+
+		// Binary files
+		{
+			name:     "text short",
+			input:    []string{"-a", "pattern", "a.bin"},
+			expected: []string{"--max-depth=0", "-a", "pattern", "a.bin"},
+		},
+		{
+			name:     "text long",
+			input:    []string{"--text", "pattern", "a.bin"},
+			expected: []string{"--max-depth=0", "-a", "pattern", "a.bin"},
+		},
+		{
+			name:     "binary files text",
+			input:    []string{"-r", "--binary-files=text", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "-a", "pattern"},
+		},
+		{
+			name:     "binary files without match",
+			input:    []string{"-r", "--binary-files=without-match", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "pattern"},
+		},
+		{
+			name:     "binary files without match separate value",
+			input:    []string{"-r", "--binary-files", "without-match", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "pattern"},
+		},
+		{
+			name:     "binary files binary",
+			input:    []string{"-r", "--binary-files=binary", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern"},
+		},
+		{
+			name:     "skip binary short",
+			input:    []string{"-rI", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "pattern"},
+		},
+		{
+			name:     "last binary option wins",
+			input:    []string{"-rIa", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "-a", "pattern"},
+		},
+		{
+			name:     "dos binary short is not multiline",
+			input:    []string{"-U", "pattern", "a.txt"},
+			expected: []string{"--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "dos binary long ignored",
+			input:    []string{"--binary", "pattern", "a.txt"},
+			expected: []string{"--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "byte offset short",
+			input:    []string{"-bo", "pattern", "a.txt"},
+			expected: []string{"-b", "-o", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "byte offset long",
+			input:    []string{"--byte-offset", "pattern", "a.txt"},
+			expected: []string{"-b", "--max-depth=0", "pattern", "a.txt"},
 		},
 
 		// Color
 		{
 			name:     "color always",
 			input:    []string{"--color=always", "pattern"},
-			expected: []string{"--color=always", "pattern"},
+			expected: []string{"--color=always", "pattern", "-"},
+		},
+		{
+			name:     "color without value",
+			input:    []string{"--color", "pattern"},
+			expected: []string{"--color=auto", "pattern", "-"},
+		},
+		{
+			name:     "colour spelling",
+			input:    []string{"--colour=always", "pattern"},
+			expected: []string{"--color=always", "pattern", "-"},
+		},
+		{
+			name:     "color force",
+			input:    []string{"--color=force", "pattern"},
+			expected: []string{"--color=always", "pattern", "-"},
+		},
+		{
+			name:     "color if-tty",
+			input:    []string{"--color=if-tty", "pattern"},
+			expected: []string{"--color=auto", "pattern", "-"},
+		},
+		{
+			name:     "color none",
+			input:    []string{"--color=none", "pattern"},
+			expected: []string{"--color=never", "pattern", "-"},
 		},
 		{
 			name:     "color never",
 			input:    []string{"--color=never", "pattern"},
-			expected: []string{"--color=never", "pattern"},
+			expected: []string{"--color=never", "pattern", "-"},
 		},
 
 		// Fixed strings
 		{
 			name:     "fixed strings",
 			input:    []string{"-F", "pattern"},
-			expected: []string{"-F", "pattern"},
+			expected: []string{"-F", "pattern", "-"},
 		},
 
 		// Pattern from file
 		{
 			name:     "pattern file",
 			input:    []string{"-f", "patterns.txt"},
-			expected: []string{"-f", "patterns.txt"},
+			expected: []string{"-f", "patterns.txt", "-"},
 		},
 
 		// Max count
 		{
 			name:     "max count",
 			input:    []string{"-m", "5", "pattern"},
-			expected: []string{"-m", "5", "pattern"},
+			expected: []string{"-m", "5", "pattern", "-"},
 		},
 
 		// Long options
 		{
-			name:     "long recursive ignored",
+			name:     "long dereference recursive",
+			input:    []string{"--dereference-recursive", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "-L", "--binary", "pattern"},
+		},
+		{
+			name:     "recursive without path searches cwd",
+			input:    []string{"-r", "SECRET"},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "SECRET"},
+		},
+		{
+			name:     "non-recursive with directory stays shallow",
+			input:    []string{"SECRET", "."},
+			expected: []string{"--max-depth=0", "SECRET", "."},
+		},
+		{
+			name:     "pattern file makes positionals paths",
+			input:    []string{"-f", "patterns.txt", "a.txt", "b.txt"},
+			expected: []string{"-f", "patterns.txt", "--max-depth=0", "a.txt", "b.txt"},
+		},
+		{
+			name:     "long recursive",
 			input:    []string{"--recursive", "pattern"},
-			expected: []string{"pattern"},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern"},
 		},
 		{
 			name:     "long extended ignored",
 			input:    []string{"--extended-regexp", "pattern"},
-			expected: []string{"pattern"},
+			expected: []string{"pattern", "-"},
+		},
+
+		// GNU long option coverage
+		{
+			name:     "exclude from",
+			input:    []string{"--exclude-from=skip.txt", "pattern", "a.txt"},
+			expected: []string{"--ignore-file", "skip.txt", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "group separator",
+			input:    []string{"-C2", "--group-separator=##", "pattern", "a.txt"},
+			expected: []string{"-C", "2", "--context-separator", "##", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "no group separator",
+			input:    []string{"-C2", "--no-group-separator", "pattern", "a.txt"},
+			expected: []string{"-C", "2", "--no-context-separator", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "line buffered",
+			input:    []string{"--line-buffered", "pattern"},
+			expected: []string{"--line-buffered", "pattern", "-"},
+		},
+		{
+			name:     "initial tab has no equivalent",
+			input:    []string{"--initial-tab", "-T", "pattern"},
+			expected: []string{"pattern", "-"},
+		},
+		{
+			name:     "label has no equivalent",
+			input:    []string{"--label=input", "-H", "pattern"},
+			expected: []string{"-H", "pattern", "-"},
+		},
+		{
+			name:     "label separate value dropped",
+			input:    []string{"--label", "input", "pattern"},
+			expected: []string{"pattern", "-"},
+		},
+		{
+			name:     "no ignore case",
+			input:    []string{"-i", "--no-ignore-case", "pattern"},
+			expected: []string{"-i", "--case-sensitive", "pattern", "-"},
+		},
+		{
+			name:     "obsolete y is ignore case",
+			input:    []string{"-y", "pattern"},
+			expected: []string{"-i", "pattern", "-"},
+		},
+		{
+			name:     "no messages short is not case sensitive",
+			input:    []string{"-s", "pattern"},
+			expected: []string{"--no-messages", "pattern", "-"},
+		},
+		{
+			name:     "no messages long",
+			input:    []string{"--no-messages", "pattern"},
+			expected: []string{"--no-messages", "pattern", "-"},
+		},
+		{
+			name:     "silent is quiet",
+			input:    []string{"--silent", "pattern"},
+			expected: []string{"--quiet", "pattern", "-"},
+		},
+		{
+			name:     "devices with value dropped",
+			input:    []string{"--devices=skip", "-D", "read", "pattern", "a.txt"},
+			expected: []string{"--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "directories recurse",
+			input:    []string{"--directories=recurse", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern"},
+		},
+		{
+			name:     "directories recurse short",
+			input:    []string{"-d", "recurse", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern"},
+		},
+		{
+			name:     "directories skip",
+			input:    []string{"--directories", "skip", "pattern", "."},
+			expected: []string{"--max-depth=0", "pattern", "."},
+		},
+		{
+			name:     "max count separate value",
+			input:    []string{"--max-count", "5", "pattern", "a.txt"},
+			expected: []string{"-m", "5", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "context number shorthand",
+			input:    []string{"-3", "pattern", "a.txt"},
+			expected: []string{"-C", "3", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "context number shorthand bundled",
+			input:    []string{"-n12i", "pattern", "a.txt"},
+			expected: []string{"-n", "-C", "12", "-i", "--max-depth=0", "pattern", "a.txt"},
+		},
+		{
+			name:     "unknown long option dropped",
+			input:    []string{"--frobnicate", "pattern"},
+			expected: []string{"pattern", "-"},
+		},
+		{
+			name:     "long ignore case",
+			input:    []string{"--ignore-case", "pattern"},
+			expected: []string{"--ignore-case", "pattern", "-"},
 		},
 
 		// Pattern starting with dash
 		{
 			name:     "pattern with dash",
 			input:    []string{"-e", "-pattern"},
-			expected: []string{"--", "-pattern"},
+			expected: []string{"--", "-pattern", "-"},
+		},
+		{
+			name:     "pattern after --",
+			input:    []string{"--", "-pattern", "file.txt"},
+			expected: []string{"--max-depth=0", "--", "-pattern", "file.txt"},
+		},
+		{
+			name:     "only files after -- with -e",
+			input:    []string{"-e", "foo", "--", "a.txt", "b.txt"},
+			expected: []string{"--max-depth=0", "foo", "a.txt", "b.txt"},
 		},
 
 		// Complex combinations
 		{
 			name:     "typical grep usage",
 			input:    []string{"-rn", "--include=*.go", "TODO", "."},
-			expected: []string{"-n", "-g", "*.go", "TODO", "."},
+			expected: []string{"-n", "-g", "*.go", "--no-ignore", "--hidden", "--binary", "TODO", "."},
 		},
 		{
 			name:     "grep with context",
 			input:    []string{"-rniA3", "pattern", "src/"},
-			expected: []string{"-n", "-i", "-A", "3", "pattern", "src/"},
+			expected: []string{"-n", "-i", "-A", "3", "--no-ignore", "--hidden", "--binary", "pattern", "src/"},
+		},
+
+		// PCRE2 auto-selection
+		{
+			name:     "backreference selects auto engine",
+			input:    []string{"-E", `(ab)\1`, "file.txt"},
+			expected: []string{"--max-depth=0", "--engine=auto", `(ab)\1`, "file.txt"},
+		},
+		{
+			name:     "lookahead selects auto engine",
+			input:    []string{"-E", "-e", "foo(?=bar)"},
+			expected: []string{"--engine=auto", "foo(?=bar)", "-"},
+		},
+		{
+			name:     "lookbehind in second pattern",
+			input:    []string{"-E", "-e", "plain", "-e", "(?<!x)y"},
+			expected: []string{"--engine=auto", "-e", "plain", "-e", "(?<!x)y", "-"},
+		},
+		{
+			name:     "basic regexp backreference",
+			input:    []string{`a\(b\)\1`, "f"},
+			expected: []string{"--max-depth=0", "--engine=auto", `a(b)\1`, "f"},
+		},
+		{
+			name:     "basic regexp literals are escaped",
+			input:    []string{"f(x) {a|b}+?"},
+			expected: []string{`f\(x\) \{a\|b\}\+\?`, "-"},
+		},
+		{
+			name:     "basic regexp interval and alternation",
+			input:    []string{"-G", `\(ab\)\{2\}\|c`},
+			expected: []string{"(ab){2}|c", "-"},
+		},
+		{
+			name:     "extended regexp is unchanged",
+			input:    []string{"--extended-regexp", "f(x)|y"},
+			expected: []string{"f(x)|y", "-"},
+		},
+		{
+			name:     "later basic regexp option wins",
+			input:    []string{"-E", "--basic-regexp", "a|b"},
+			expected: []string{`a\|b`, "-"},
+		},
+		{
+			name:     "escaped backslash is not a backreference",
+			input:    []string{`a\\1`},
+			expected: []string{`a\\1`, "-"},
+		},
+		{
+			name:     "fixed strings keep default engine",
+			input:    []string{"-F", `(ab)\1`},
+			expected: []string{"-F", `(ab)\1`, "-"},
+		},
+		{
+			name:     "explicit perl regexp not duplicated",
+			input:    []string{"-P", `(ab)\1`},
+			expected: []string{"-P", `(ab)\1`, "-"},
 		},
 
 		// Empty input
@@ -244,7 +578,7 @@ func TestTranslateFlags(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := translateFlags(tt.input)
+			result := translateFlags(tt.input, translator.Options{Mode: "gnu"}, Variant{})
 			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
 			}
@@ -252,6 +586,385 @@ func TestTranslateFlags(t *testing.T) {
 	}
 }
 
+func TestTranslateFlagsBSD(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "Z decompresses instead of null",
+			input:    []string{"-Z", "pattern", "log.gz"},
+			expected: []string{"-z", "--max-depth=0", "pattern", "log.gz"},
+		},
+		{
+			name:     "J decompresses bzip2",
+			input:    []string{"-J", "pattern", "log.bz2"},
+			expected: []string{"-z", "--max-depth=0", "pattern", "log.bz2"},
+		},
+		{
+			name:     "long decompress",
+			input:    []string{"--decompress", "pattern", "log.gz"},
+			expected: []string{"-z", "--max-depth=0", "pattern", "log.gz"},
+		},
+		{
+			name:     "null long same as GNU",
+			input:    []string{"-l", "--null", "pattern", "a.txt", "b.txt"},
+			expected: []string{"-l", "-0", "--max-depth=0", "pattern", "a.txt", "b.txt"},
+		},
+		{
+			name:     "z is null data",
+			input:    []string{"-z", "pattern"},
+			expected: []string{"--null-data", "pattern", "-"},
+		},
+		{
+			name:     "R does not follow symlinks",
+			input:    []string{"-R", "pattern", "."},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern", "."},
+		},
+		{
+			name:     "S follows all symlinks",
+			input:    []string{"-RS", "pattern", "."},
+			expected: []string{"--no-ignore", "--hidden", "-L", "--binary", "pattern", "."},
+		},
+		{
+			name:     "O follows command line symlinks only",
+			input:    []string{"-rSO", "pattern", "."},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern", "."},
+		},
+		{
+			name:     "p follows no symlinks",
+			input:    []string{"-Rp", "pattern", "."},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern", "."},
+		},
+		{
+			name:     "U searches binary files without printing",
+			input:    []string{"-rIU", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern"},
+		},
+		{
+			name:     "long binary searches binary files",
+			input:    []string{"-r", "--binary-files=text", "--binary", "pattern"},
+			expected: []string{"--no-ignore", "--hidden", "--binary", "pattern"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, translator.Options{Mode: "bsd"}, Variant{})
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetGrepModeParameter(t *testing.T) {
+	tests := []struct {
+		mode     string
+		expected GrepMode
+	}{
+		{"bsd", ModeBSD},
+		{"BSD", ModeBSD},
+		{"gnu", ModeGNU},
+		{"GNU", ModeGNU},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if got := getGrepMode(tt.mode); got != tt.expected {
+				t.Errorf("getGrepMode(%q) = %v, want %v", tt.mode, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslateFlagsPiped(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "stdin has no filename",
+			input:    []string{"foo"},
+			expected: []string{"--no-config", "--no-heading", "--no-filename", "foo", "-"},
+		},
+		{
+			name:     "single file has no filename",
+			input:    []string{"-n", "foo", "a.txt"},
+			expected: []string{"-n", "--max-depth=0", "--no-config", "--no-heading", "--no-filename", "foo", "a.txt"},
+		},
+		{
+			name:     "pattern after -- and a single file",
+			input:    []string{"--", "foo", "a.txt"},
+			expected: []string{"--max-depth=0", "--no-config", "--no-heading", "--no-filename", "foo", "a.txt"},
+		},
+		{
+			name:     "multiple files have filenames",
+			input:    []string{"foo", "a.txt", "b.txt"},
+			expected: []string{"--max-depth=0", "--no-config", "--no-heading", "--with-filename", "foo", "a.txt", "b.txt"},
+		},
+		{
+			name:     "recursive has filenames",
+			input:    []string{"-rn", "foo", "."},
+			expected: []string{"-n", "--no-ignore", "--hidden", "--binary", "--no-config", "--no-heading", "--with-filename", "foo", "."},
+		},
+		{
+			name:     "explicit -H kept",
+			input:    []string{"-H", "foo", "a.txt"},
+			expected: []string{"-H", "--max-depth=0", "--no-config", "--no-heading", "foo", "a.txt"},
+		},
+		{
+			name:     "explicit -h kept",
+			input:    []string{"-rh", "foo", "."},
+			expected: []string{"--no-filename", "--no-ignore", "--hidden", "--binary", "--no-config", "--no-heading", "foo", "."},
+		},
+		{
+			name:     "long no-filename kept",
+			input:    []string{"--no-filename", "foo", "a.txt", "b.txt"},
+			expected: []string{"--no-filename", "--max-depth=0", "--no-config", "--no-heading", "foo", "a.txt", "b.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, translator.Options{Mode: "gnu", Piped: true}, Variant{})
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslateGrepColors(t *testing.T) {
+	tests := []struct {
+		name       string
+		grepColors string
+		grepColor  string
+		expected   []string
+	}{
+		{
+			name:     "unset",
+			expected: nil,
+		},
+		{
+			name:       "GNU defaults",
+			grepColors: "ms=01;31:mc=01;31:sl=:cx=:fn=35:ln=32:bn=32:se=36",
+			expected: []string{
+				"--colors=match:none", "--colors=match:style:bold", "--colors=match:fg:red",
+				"--colors=path:none", "--colors=path:fg:magenta",
+				"--colors=line:none", "--colors=line:fg:green",
+				"--colors=column:none", "--colors=column:fg:green",
+			},
+		},
+		{
+			name:       "mt sets match",
+			grepColors: "mt=04;34",
+			expected:   []string{"--colors=match:none", "--colors=match:style:underline", "--colors=match:fg:blue"},
+		},
+		{
+			name:       "background and bright colors",
+			grepColors: "ms=30;43:fn=95",
+			expected: []string{
+				"--colors=match:none", "--colors=match:fg:black", "--colors=match:bg:yellow",
+				"--colors=path:none", "--colors=path:fg:magenta", "--colors=path:style:intense",
+			},
+		},
+		{
+			name:       "256 and truecolor",
+			grepColors: "ms=38;5;208:ln=48;2;255;0;128",
+			expected: []string{
+				"--colors=match:none", "--colors=match:fg:208",
+				"--colors=line:none", "--colors=line:bg:0xff,0x00,0x80",
+			},
+		},
+		{
+			name:       "boolean capabilities ignored",
+			grepColors: "rv:ne:fn=36",
+			expected:   []string{"--colors=path:none", "--colors=path:fg:cyan"},
+		},
+		{
+			name:      "deprecated GREP_COLOR",
+			grepColor: "01;32",
+			expected:  []string{"--colors=match:none", "--colors=match:style:bold", "--colors=match:fg:green"},
+		},
+		{
+			name:       "GREP_COLORS overrides GREP_COLOR",
+			grepColors: "ms=31",
+			grepColor:  "32",
+			expected:   []string{"--colors=match:none", "--colors=match:fg:red"},
+		},
+		{
+			name:       "empty value clears color",
+			grepColors: "fn=",
+			expected:   []string{"--colors=path:none"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateGrepColors(tt.grepColors, tt.grepColor)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateGrepColors(%q, %q) = %v, want %v", tt.grepColors, tt.grepColor, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslateFlagsGrepColorsEnv(t *testing.T) {
+	opts := translator.Options{
+		Mode: "gnu",
+		Env:  map[string]string{"GREP_COLORS": "ms=01;31:fn=35"},
+	}
+	input := []string{"-n", "foo", "a.txt"}
+	expected := []string{
+		"-n", "--max-depth=0",
+		"--colors=match:none", "--colors=match:style:bold", "--colors=match:fg:red",
+		"--colors=path:none", "--colors=path:fg:magenta",
+		"foo", "a.txt",
+	}
+
+	result := translateFlags(input, opts, Variant{})
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("translateFlags(%v) = %v, want %v", input, result, expected)
+	}
+}
+
+func TestBasicToExtended(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{"plain", "plain"},
+		{`\(a\)\1`, `(a)\1`},
+		{"(a)", `\(a\)`},
+		{`a\{1,3\}`, "a{1,3}"},
+		{"a{1,3}", `a\{1,3\}`},
+		{`a\|b`, "a|b"},
+		{`a\+\?`, "a+?"},
+		{`\.\*\\`, `\.\*\\`},
+		{"*a", `\*a`},
+		{"^*a", `^\*a`},
+		{`\(*a\)`, `(\*a)`},
+		{"(*", `\(*`},
+		{"a*", "a*"},
+		{"[(|)]", "[(|)]"},
+		{"[]|]x|", `[]|]x\|`},
+		{"[[:alpha:]|]+", `[[:alpha:]|]\+`},
+		{"[^)]", "[^)]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := basicToExtended(tt.pattern); got != tt.expected {
+				t.Errorf("basicToExtended(%q) = %q, want %q", tt.pattern, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNeedsPCRE2(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected bool
+	}{
+		{"plain", false},
+		{"foo|bar", false},
+		{`\d+\s*`, false},
+		{`(a)\1`, true},
+		{`\\1`, false},
+		{`\\\1`, true},
+		{"a(?=b)", true},
+		{"a(?!b)", true},
+		{"(?<=a)b", true},
+		{"(?<!a)b", true},
+		{"(?>a+)", true},
+		{`(?<n>a)\k<n>`, true},
+		{`foo\Kbar`, true},
+		{"(?i)case", false},
+		{"(?P<name>x)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := needsPCRE2(tt.pattern); got != tt.expected {
+				t.Errorf("needsPCRE2(%q) = %v, want %v", tt.pattern, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPatternFileSelectsEngine(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.txt")
+	backref := filepath.Join(dir, "backref.txt")
+	if err := os.WriteFile(plain, []byte("foo\nbar\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(backref, []byte("foo\n(x)\\1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "plain pattern file",
+			input:    []string{"-f", plain, "--max-depth=0", "."},
+			expected: []string{"-f", plain, "--max-depth=0", "."},
+		},
+		{
+			name:     "backreference in pattern file",
+			input:    []string{"-f", backref, "."},
+			expected: []string{"-f", backref, "--max-depth=0", "--engine=auto", "."},
+		},
+		{
+			name:     "long option pattern file",
+			input:    []string{"--file=" + backref, "."},
+			expected: []string{"-f", backref, "--max-depth=0", "--engine=auto", "."},
+		},
+		{
+			name:     "missing pattern file",
+			input:    []string{"-f", filepath.Join(dir, "missing.txt"), "."},
+			expected: []string{"-f", filepath.Join(dir, "missing.txt"), "--max-depth=0", "."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, translator.Options{Mode: "gnu"}, Variant{})
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHelpRunsGrep(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		fallback bool
+	}{
+		{"help", []string{"--help"}, true},
+		{"help after other flags", []string{"-i", "--help"}, true},
+		{"help after --", []string{"--", "--help"}, false},
+		{"pattern", []string{"help"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := TranslateVariant(tt.input, translator.Options{Mode: "gnu"}, Variant{})
+			if result.Fallback != tt.fallback {
+				t.Errorf("TranslateVariant(%v) fallback = %v, want %v", tt.input, result.Fallback, tt.fallback)
+			}
+		})
+	}
+}
+
 func TestTranslatorInterface(t *testing.T) {
 	tr := &Translator{}
 
@@ -19,8 +19,8 @@ func (t *Translator) TargetTool() string  { return "moor" }
 func (t *Translator) IncludeInInit() bool { return true }
 
 // Translate converts less arguments to moor arguments
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args)
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translator.Result{Args: translateFlags(args)}
 }
 
 // Simple 1:1 flag mappings from less to moor
@@ -3,6 +3,8 @@ package less2moor
 import (
 	"reflect"
 	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
 )
 
 func TestTranslateFlags(t *testing.T) {
@@ -249,7 +251,7 @@ func TestTranslator(t *testing.T) {
 	// Test Translate method
 	input := []string{"-S", "file.txt"}
 	expected := []string{"--wrap=false", "file.txt"}
-	result := tr.Translate(input, "")
+	result := tr.Translate(input, translator.Options{}).Args
 
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("Translate(%v) = %v, want %v", input, result, expected)
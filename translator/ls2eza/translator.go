@@ -2,6 +2,8 @@ package ls2eza
 
 import (
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/kluzzebass/reflag/translator"
@@ -20,8 +22,8 @@ func (t *Translator) TargetTool() string  { return "eza" }
 func (t *Translator) IncludeInInit() bool { return true }
 
 // Translate converts ls arguments to eza arguments
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args, getLSMode(mode))
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translateFlags(args, GetLSMode(opts.Mode), opts)
 }
 
 // LSMode determines which ls flavor to emulate
@@ -32,8 +34,8 @@ const (
 	ModeGNU
 )
 
-// getLSMode returns the ls compatibility mode based on mode string or OS detection
-func getLSMode(mode string) LSMode {
+// GetLSMode returns the ls compatibility mode based on mode string or OS detection
+func GetLSMode(mode string) LSMode {
 	switch strings.ToLower(mode) {
 	case "bsd":
 		return ModeBSD
@@ -59,6 +61,75 @@ var reverseNeeded = map[rune]bool{
 	'U': true, // creation time sort (BSD)
 }
 
+// GNU ls --sort=WORD values and their eza equivalents
+// An empty mapping means eza has no equivalent and the sort is dropped
+var sortWordMap = map[string]string{
+	"none":      "none",
+	"name":      "name",
+	"size":      "size",
+	"time":      "modified",
+	"version":   "name", // eza's name sort is natural, like version sort
+	"extension": "extension",
+	"width":     "", // sort by name width, no eza equivalent
+}
+
+// GNU ls sort words that need --reverse in eza, like their short flags
+var reverseNeededSort = map[string]bool{
+	"size": true, // like -S
+	"time": true, // like -t
+}
+
+// GNU ls --time=WORD values and their eza equivalents
+var timeWordMap = map[string]string{
+	"atime":        "accessed",
+	"access":       "accessed",
+	"use":          "accessed",
+	"ctime":        "changed",
+	"status":       "changed",
+	"mtime":        "modified",
+	"modification": "modified",
+	"birth":        "created",
+	"creation":     "created",
+}
+
+// SizeFormat is how file sizes are shown in the long view
+// ls shows raw bytes unless -h, --si or a block size says otherwise
+type SizeFormat int
+
+const (
+	SizeBytes  SizeFormat = iota // exact byte counts
+	SizeBinary                   // powers of 1024, like -h
+	SizeSI                       // powers of 1000, like --si
+)
+
+// eza size flags; eza is human-readable with decimal prefixes by default
+var sizeFlags = map[SizeFormat]string{
+	SizeBytes:  "--bytes",
+	SizeBinary: "--binary",
+}
+
+// BlockSizeFormat maps a GNU ls block size (--block-size, BLOCK_SIZE or
+// LS_BLOCK_SIZE) to the closest size format
+func BlockSizeFormat(size string) SizeFormat {
+	// A leading quote only adds thousands separators
+	size = strings.TrimPrefix(size, "'")
+	switch {
+	case size == "human-readable":
+		return SizeBinary
+	case size == "si":
+		return SizeSI
+	case strings.HasSuffix(size, "B") && !strings.HasSuffix(size, "iB"):
+		// KB, MB, ... are powers of 1000
+		return SizeSI
+	case strings.TrimLeft(size, "0123456789") == "":
+		// Plain numbers; eza can't scale by arbitrary units
+		return SizeBytes
+	default:
+		// K, M, KiB, MiB, ... are powers of 1024
+		return SizeBinary
+	}
+}
+
 // Simple 1:1 flag mappings
 var flagMap = map[rune][]string{
 	// Display format
@@ -84,14 +155,9 @@ var flagMap = map[rune][]string{
 	'v': {"--sort=name"},       // natural version sort
 
 	// File size display
-	'h': {},              // human-readable (default in eza)
-	'k': {},              // 1024-byte blocks
+	'k': {},              // 1024-byte blocks (only affects -s and totals)
 	's': {"--blocksize"}, // show allocated blocks
 
-	// Indicators and classification
-	'F': {"-F"},         // append file type indicators
-	'p': {"--classify"}, // append / to directories
-
 	// Long format options
 	'i': {"--inode"},          // show inode numbers
 	'n': {"--numeric"},        // numeric user/group IDs
@@ -110,15 +176,11 @@ var flagMap = map[rune][]string{
 	'G': {}, // color output (default in eza)
 
 	// Misc BSD
-	'q': {}, // replace non-printable with ?
-	'b': {}, // C-style escapes
 	'B': {}, // octal escapes (BSD) / ignore-backups (GNU)
 	'W': {}, // display whiteouts (BSD)
 
 	// GNU ls specific
-	'Z': {"-Z"},          // SELinux security context
-	'N': {"--no-quotes"}, // print names without quoting
-	'Q': {},              // quote names
+	'Z': {"-Z"}, // SELinux security context
 }
 
 // Long option mappings
@@ -127,11 +189,8 @@ var longFlagMap = map[string][]string{
 	"--almost-all":      {"-A"},
 	"--directory":       {"-d"},
 	"--recursive":       {"--recurse"},
-	"--human-readable":  {},
 	"--inode":           {"--inode"},
 	"--numeric-uid-gid": {"--numeric"},
-	"--classify":        {"-F"},
-	"--file-type":       {"--classify"},
 	"--dereference":     {"-X"},
 	"--no-group":        {"--no-group"},
 
@@ -139,17 +198,11 @@ var longFlagMap = map[string][]string{
 	"--reverse":                 {"--reverse"},
 	"--size":                    {"--blocksize"},
 	"--context":                 {"-Z"},
-	"--literal":                 {"--no-quotes"},
-	"--quote-name":              {},
-	"--hide-control-chars":      {},
-	"--show-control-chars":      {},
 	"--hyperlink":               {"--hyperlink"},
 	"--full-time":               {"-l", "--time-style=full-iso"},
 	"--author":                  {},
-	"--escape":                  {},
 	"--ignore-backups":          {},
-	"--kibibytes":               {},
-	"--si":                      {},
+	"--kibibytes":               {}, // only affects -s and totals
 	"--dired":                   {},
 	"--zero":                    {},
 }
@@ -159,27 +212,157 @@ var longFlagPrefixes = []struct {
 	prefix string
 	pass   bool
 }{
-	{"--color", true},
-	{"--colour", true},
-	{"--sort=", true},
-	{"--time=", true},
-	{"--time-style=", true},
 	{"--hyperlink=", true},
 	{"--width=", true},
 	{"--ignore=", true},
 	{"--hide=", false},
-	{"--block-size=", false},
-	{"--indicator-style=", false},
-	{"--quoting-style=", false},
 	{"--tabsize=", false},
 }
 
-func translateFlags(args []string, mode LSMode) []string {
+// Short flags that select an indicator style
+var indicatorFlags = map[rune]string{
+	'F': "classify",
+	'p': "slash",
+}
+
+// Short flags that select a quoting style
+var quotingFlags = map[rune]string{
+	'N': "literal",
+	'Q': "c",
+	'b': "escape",
+}
+
+// Long options that select an indicator or quoting style
+var longStyleMap = map[string]struct{ indicator, quoting string }{
+	"--classify":   {indicator: "classify"},
+	"--file-type":  {indicator: "file-type"},
+	"--literal":    {quoting: "literal"},
+	"--quote-name": {quoting: "c"},
+	"--escape":     {quoting: "escape"},
+}
+
+// TranslateWhen converts a GNU ls WHEN argument (--classify, --color) to always,
+// auto or never; an empty WHEN means always
+func TranslateWhen(when string) string {
+	switch when {
+	case "", "always", "yes", "force":
+		return "always"
+	case "never", "no", "none":
+		return "never"
+	default: // "auto", "tty", "if-tty"
+		return "auto"
+	}
+}
+
+// translateIndicatorStyle converts an ls indicator style to eza flags
+// eza's --classify marks every file type, so the narrower ls styles can't be matched exactly
+func translateIndicatorStyle(style, when string) (flags []string, warning string) {
+	switch style {
+	case "classify":
+		return []string{"--classify=" + when}, ""
+	case "slash":
+		return []string{"--classify=always"}, "eza can't mark only directories, --classify also marks executables, links, pipes and sockets"
+	case "file-type":
+		return []string{"--classify=always"}, "eza can't leave executables unmarked, --classify marks every file type"
+	}
+	// "none" is eza's default
+	return nil, ""
+}
+
+// translateQuotingStyle converts an ls quoting style to eza flags
+// eza quotes names only when they contain spaces or shell characters, and
+// always escapes control characters
+func translateQuotingStyle(style string) (flags []string, warning string) {
+	switch style {
+	case "literal":
+		return []string{"--no-quotes"}, ""
+	case "shell", "shell-escape":
+		// eza's default
+		return nil, ""
+	case "shell-always", "shell-escape-always":
+		return nil, "eza can't quote every name, names are quoted only when needed"
+	case "escape":
+		return []string{"--no-quotes"}, "eza escapes only control characters, not spaces or other special characters"
+	case "c", "locale", "clocale":
+		return nil, "eza has no " + style + " quoting style, names are quoted only when needed"
+	}
+	return nil, ""
+}
+
+// BSD LSCOLORS positions and the LS_COLORS keys eza uses for them
+var lscolorsKeys = []string{
+	"di", // directory
+	"ln", // symbolic link
+	"so", // socket
+	"pi", // pipe
+	"ex", // executable
+	"bd", // block special
+	"cd", // character special
+	"su", // executable with setuid bit
+	"sg", // executable with setgid bit
+	"tw", // directory writable to others, with sticky bit
+	"ow", // directory writable to others, without sticky bit
+}
+
+// TranslateLSColors converts BSD LSCOLORS letter pairs to an LS_COLORS-style
+// value for EZA_COLORS
+// Each pair is a foreground and a background letter: a-h are black, red,
+// green, brown, blue, magenta, cyan and light grey, uppercase is bold, and
+// x is the default color
+func TranslateLSColors(lscolors string) string {
+	var entries []string
+	for i, key := range lscolorsKeys {
+		if len(lscolors) < 2*i+2 {
+			break
+		}
+		fg, bg := lscolors[2*i], lscolors[2*i+1]
+
+		var codes []string
+		switch {
+		case fg >= 'a' && fg <= 'h':
+			codes = append(codes, strconv.Itoa(30+int(fg-'a')))
+		case fg >= 'A' && fg <= 'H':
+			codes = append(codes, "1", strconv.Itoa(30+int(fg-'A')))
+		}
+		switch {
+		case bg >= 'a' && bg <= 'h':
+			codes = append(codes, strconv.Itoa(40+int(bg-'a')))
+		case bg >= 'A' && bg <= 'H':
+			codes = append(codes, strconv.Itoa(40+int(bg-'A')))
+		}
+		if len(codes) > 0 {
+			entries = append(entries, key+"="+strings.Join(codes, ";"))
+		}
+	}
+	return strings.Join(entries, ":")
+}
+
+func translateFlags(args []string, mode LSMode, opts translator.Options) translator.Result {
 	var ezaArgs []string
 	var paths []string
+	var warnings []string
 	userReverse := false
 	needsReverse := false
 	skipNext := false
+	timeField := ""
+	sizeFormat := SizeBytes
+	endOfOptions := false
+	indicatorStyle := ""
+	classify := ""
+	quotingStyle := ""
+	controlChars := ""
+	color := ""
+
+	if mode == ModeGNU {
+		quotingStyle = opts.Getenv("QUOTING_STYLE")
+
+		// LS_BLOCK_SIZE takes precedence over BLOCK_SIZE
+		if size := opts.Getenv("LS_BLOCK_SIZE"); size != "" {
+			sizeFormat = BlockSizeFormat(size)
+		} else if size := opts.Getenv("BLOCK_SIZE"); size != "" {
+			sizeFormat = BlockSizeFormat(size)
+		}
+	}
 
 	for i, arg := range args {
 		if skipNext {
@@ -187,12 +370,134 @@ func translateFlags(args []string, mode LSMode) []string {
 			continue
 		}
 
+		// Everything after -- is a file, even if it starts with a dash
+		if endOfOptions {
+			paths = append(paths, arg)
+			continue
+		}
+		if arg == "--" {
+			endOfOptions = true
+			continue
+		}
+
 		if strings.HasPrefix(arg, "--") {
 			if arg == "--reverse" {
 				userReverse = true
 				continue
 			}
 
+			if (arg == "--sort" || arg == "--time") && i+1 < len(args) {
+				// The word can also be given as a separate argument
+				arg += "=" + args[i+1]
+				skipNext = true
+			}
+
+			if word, ok := strings.CutPrefix(arg, "--sort="); ok {
+				field, known := sortWordMap[word]
+				if !known {
+					// Already in eza's vocabulary (or unknown to both)
+					field = word
+				}
+				if field != "" {
+					ezaArgs = append(ezaArgs, "--sort="+field)
+				}
+				// The last sort decides whether eza's order must be flipped
+				needsReverse = reverseNeededSort[word]
+				continue
+			}
+
+			switch arg {
+			case "--human-readable":
+				sizeFormat = SizeBinary
+				continue
+			case "--si":
+				sizeFormat = SizeSI
+				continue
+			case "--block-size":
+				if i+1 < len(args) {
+					sizeFormat = BlockSizeFormat(args[i+1])
+					skipNext = true
+				}
+				continue
+			}
+			if size, ok := strings.CutPrefix(arg, "--block-size="); ok {
+				sizeFormat = BlockSizeFormat(size)
+				continue
+			}
+
+			if style, ok := longStyleMap[arg]; ok {
+				if style.indicator != "" {
+					indicatorStyle = style.indicator
+					classify = TranslateWhen("")
+				} else {
+					quotingStyle = style.quoting
+				}
+				continue
+			}
+			if arg == "--color" || arg == "--colour" {
+				color = TranslateWhen("")
+				continue
+			}
+			if when, ok := strings.CutPrefix(arg, "--color="); ok {
+				color = TranslateWhen(when)
+				continue
+			}
+			if when, ok := strings.CutPrefix(arg, "--colour="); ok {
+				color = TranslateWhen(when)
+				continue
+			}
+
+			if when, ok := strings.CutPrefix(arg, "--classify="); ok {
+				indicatorStyle = "classify"
+				classify = TranslateWhen(when)
+				continue
+			}
+			if arg == "--hide-control-chars" {
+				controlChars = "hide"
+				continue
+			}
+			if arg == "--show-control-chars" {
+				controlChars = "show"
+				continue
+			}
+
+			if (arg == "--indicator-style" || arg == "--quoting-style") && i+1 < len(args) {
+				// The style can also be given as a separate argument
+				arg += "=" + args[i+1]
+				skipNext = true
+			}
+			if style, ok := strings.CutPrefix(arg, "--indicator-style="); ok {
+				indicatorStyle = style
+				classify = TranslateWhen("")
+				continue
+			}
+			if style, ok := strings.CutPrefix(arg, "--quoting-style="); ok {
+				quotingStyle = style
+				continue
+			}
+
+			if arg == "--time-style" && i+1 < len(args) {
+				// The style can also be given as a separate argument
+				arg = "--time-style=" + args[i+1]
+				skipNext = true
+			}
+			if style, ok := strings.CutPrefix(arg, "--time-style="); ok {
+				if mapped := TranslateTimeStyle(style, opts); mapped != "" {
+					ezaArgs = append(ezaArgs, "--time-style="+mapped)
+				}
+				continue
+			}
+
+			if word, ok := strings.CutPrefix(arg, "--time="); ok {
+				if mapped, known := timeWordMap[word]; known {
+					timeField = mapped
+				} else {
+					timeField = word
+				}
+				ezaArgs = append(ezaArgs, "--time="+timeField)
+				continue
+			}
+
 			handled := false
 			for _, pf := range longFlagPrefixes {
 				if strings.HasPrefix(arg, pf.prefix) {
@@ -223,18 +528,35 @@ func translateFlags(args []string, mode LSMode) []string {
 					userReverse = true
 					continue
 				}
+				if c == 'h' {
+					sizeFormat = SizeBinary
+					continue
+				}
+				if style, ok := indicatorFlags[c]; ok {
+					indicatorStyle = style
+					classify = TranslateWhen("")
+					continue
+				}
+				if style, ok := quotingFlags[c]; ok {
+					quotingStyle = style
+					continue
+				}
+				if c == 'q' {
+					controlChars = "hide"
+					continue
+				}
 				if c == 'D' {
 					if mode == ModeBSD {
 						remaining := flags[j+1:]
 						var format string
 						if len(remaining) > 0 {
 							format = string(remaining)
-						} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+						} else if i+1 < len(args) {
 							format = args[i+1]
 							skipNext = true
 						}
 						if format != "" {
-							ezaArgs = append(ezaArgs, "--time-style=+"+format)
+							ezaArgs = append(ezaArgs, "--time-style="+TranslateTimeStyle("+"+format, opts))
 						}
 						break
 					}
@@ -246,7 +568,7 @@ func translateFlags(args []string, mode LSMode) []string {
 						var pattern string
 						if len(remaining) > 0 {
 							pattern = string(remaining)
-						} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+						} else if i+1 < len(args) {
 							pattern = args[i+1]
 							skipNext = true
 						}
@@ -263,7 +585,7 @@ func translateFlags(args []string, mode LSMode) []string {
 						var width string
 						if len(remaining) > 0 {
 							width = string(remaining)
-						} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+						} else if i+1 < len(args) {
 							width = args[i+1]
 							skipNext = true
 						}
@@ -272,6 +594,8 @@ func translateFlags(args []string, mode LSMode) []string {
 						}
 						break
 					}
+					// BSD -w prints non-printable characters raw
+					controlChars = "show"
 					continue
 				}
 				if c == 'T' {
@@ -282,7 +606,7 @@ func translateFlags(args []string, mode LSMode) []string {
 					remaining := flags[j+1:]
 					if len(remaining) > 0 {
 						break
-					} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+					} else if i+1 < len(args) {
 						skipNext = true
 					}
 					continue
@@ -307,10 +631,66 @@ func translateFlags(args []string, mode LSMode) []string {
 		}
 	}
 
+	// GNU ls falls back to TIME_STYLE when no style is given
+	if style := opts.Getenv("TIME_STYLE"); style != "" && mode == ModeGNU && !hasTimeStyle(ezaArgs) {
+		if mapped := TranslateTimeStyle(style, opts); mapped != "" {
+			ezaArgs = append(ezaArgs, "--time-style="+mapped)
+		}
+	}
+
+	// GNU ls sorts by the timestamp chosen with --time when sorting by time
+	if timeField != "" {
+		for i, f := range ezaArgs {
+			if f == "--sort=modified" {
+				ezaArgs[i] = "--sort=" + timeField
+			}
+		}
+	}
+
 	if needsReverse != userReverse {
 		ezaArgs = append(ezaArgs, "--reverse")
 	}
 
+	flags, warning := translateIndicatorStyle(indicatorStyle, classify)
+	ezaArgs = append(ezaArgs, flags...)
+	if warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	flags, warning = translateQuotingStyle(quotingStyle)
+	ezaArgs = append(ezaArgs, flags...)
+	if warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	// BSD ls colors even when piped if CLICOLOR_FORCE is set
+	if color == "" && mode == ModeBSD && opts.Getenv("CLICOLOR_FORCE") != "" {
+		color = "always"
+	}
+	if color != "" {
+		ezaArgs = append(ezaArgs, "--color="+color)
+	}
+
+	// eza reads LS_COLORS but not BSD's LSCOLORS, and colors the user set
+	// in either variable take precedence
+	var env map[string]string
+	if lscolors := opts.Getenv("LSCOLORS"); lscolors != "" && mode == ModeBSD && opts.Getenv("EZA_COLORS") == "" && opts.Getenv("LS_COLORS") == "" {
+		if colors := TranslateLSColors(lscolors); colors != "" {
+			env = map[string]string{"EZA_COLORS": colors}
+		}
+	}
+
+	switch controlChars {
+	case "hide":
+		warnings = append(warnings, "eza shows non-printable characters as escapes, not as ?")
+	case "show":
+		warnings = append(warnings, "eza can't print non-printable characters raw, they are shown as escapes")
+	}
+
+	if flag := sizeFlags[sizeFormat]; flag != "" && slices.Contains(ezaArgs, "-l") {
+		ezaArgs = append(ezaArgs, flag)
+	}
+
 	seen := make(map[string]bool)
 	var deduped []string
 	for _, f := range ezaArgs {
@@ -320,5 +700,108 @@ func translateFlags(args []string, mode LSMode) []string {
 		}
 	}
 
-	return append(deduped, paths...)
+	// Keep files that look like flags away from eza's option parser
+	if endOfOptions && len(paths) > 0 {
+		deduped = append(deduped, "--")
+	}
+
+	return translator.Result{Args: append(deduped, paths...), Env: env, Warnings: warnings}
+}
+
+// hasTimeStyle reports whether a --time-style flag was already emitted
+func hasTimeStyle(ezaArgs []string) bool {
+	for _, f := range ezaArgs {
+		if strings.HasPrefix(f, "--time-style=") {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslateTimeStyle converts an ls time style to a valid eza --time-style value
+// Returns "" if the style can't be expressed
+func TranslateTimeStyle(style string, opts translator.Options) string {
+	// posix-STYLE only applies STYLE outside the POSIX locale
+	if rest, ok := strings.CutPrefix(style, "posix-"); ok {
+		if isPOSIXLocale(opts) {
+			return "default"
+		}
+		style = rest
+	}
+
+	switch style {
+	case "full-iso", "long-iso", "iso":
+		return style
+	case "locale":
+		// eza's default style is the closest to the locale's format
+		return "default"
+	case "":
+		return ""
+	}
+
+	if format, ok := strings.CutPrefix(style, "+"); ok {
+		// +FMT1\nFMT2 uses FMT1 for old files and FMT2 for recent ones;
+		// eza has a single format, so keep the one that includes the year
+		format, _, _ = strings.Cut(format, "\n")
+		return "+" + convertStrftime(format)
+	}
+
+	// eza's own styles (e.g. "relative") pass through
+	return style
+}
+
+// isPOSIXLocale reports whether timestamps are formatted in the C/POSIX locale
+func isPOSIXLocale(opts translator.Options) bool {
+	for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if val := opts.Getenv(key); val != "" {
+			return val == "C" || val == "POSIX"
+		}
+	}
+	return true
+}
+
+// convertStrftime converts a C strftime format (with GNU extensions) to the
+// chrono format eza uses
+func convertStrftime(format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 >= len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		b.WriteByte('%')
+		i++
+
+		// Case flags (^ and #) have no chrono equivalent
+		for i < len(format) && (format[i] == '^' || format[i] == '#') {
+			i++
+		}
+		// Padding flags (-, _, 0) are supported by chrono
+		if i < len(format) && (format[i] == '-' || format[i] == '_' || format[i] == '0') {
+			b.WriteByte(format[i])
+			i++
+		}
+		// Field width, only meaningful for %N
+		width := ""
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			width += string(format[i])
+			i++
+		}
+		// E and O modifiers select alternative locale representations
+		if i < len(format) && (format[i] == 'E' || format[i] == 'O') {
+			i++
+		}
+		if i >= len(format) {
+			break
+		}
+
+		switch format[i] {
+		case 'N':
+			// GNU nanoseconds, %3N for milliseconds
+			b.WriteString(width + "f")
+		default:
+			b.WriteByte(format[i])
+		}
+	}
+	return b.String()
 }
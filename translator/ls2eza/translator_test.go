@@ -3,6 +3,8 @@ package ls2eza
 import (
 	"reflect"
 	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
 )
 
 func TestTranslateFlagsGNU(t *testing.T) {
@@ -15,7 +17,7 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "long format",
 			input:    []string{"-l"},
-			expected: []string{"-l"},
+			expected: []string{"-l", "--bytes"},
 		},
 		{
 			name:     "all files",
@@ -25,7 +27,7 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "combined la",
 			input:    []string{"-la"},
-			expected: []string{"-l", "-a"},
+			expected: []string{"-l", "-a", "--bytes"},
 		},
 		{
 			name:     "almost all",
@@ -84,17 +86,17 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "long time sort",
 			input:    []string{"-lt"},
-			expected: []string{"-l", "--sort=modified", "--reverse"},
+			expected: []string{"-l", "--sort=modified", "--reverse", "--bytes"},
 		},
 		{
 			name:     "long time sort reversed",
 			input:    []string{"-ltr"},
-			expected: []string{"-l", "--sort=modified"},
+			expected: []string{"-l", "--sort=modified", "--bytes"},
 		},
 		{
 			name:     "long size sort with path",
 			input:    []string{"-lS", "/tmp"},
-			expected: []string{"-l", "--sort=size", "--reverse", "/tmp"},
+			expected: []string{"-l", "--sort=size", "--reverse", "--bytes", "/tmp"},
 		},
 
 		// Flags that map to empty (defaults in eza)
@@ -109,9 +111,9 @@ func TestTranslateFlagsGNU(t *testing.T) {
 			expected: nil,
 		},
 		{
-			name:     "lh only outputs l",
+			name:     "lh uses binary prefixes",
 			input:    []string{"-lh"},
-			expected: []string{"-l"},
+			expected: []string{"-l", "--binary"},
 		},
 
 		// Long format options
@@ -128,12 +130,12 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "no group",
 			input:    []string{"-o"},
-			expected: []string{"-l", "--no-group"},
+			expected: []string{"-l", "--no-group", "--bytes"},
 		},
 		{
 			name:     "no user",
 			input:    []string{"-g"},
-			expected: []string{"-l", "--no-user"},
+			expected: []string{"-l", "--no-user", "--bytes"},
 		},
 		{
 			name:     "file flags",
@@ -172,12 +174,12 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "classify F",
 			input:    []string{"-F"},
-			expected: []string{"-F"},
+			expected: []string{"--classify=always"},
 		},
 		{
 			name:     "classify p",
 			input:    []string{"-p"},
-			expected: []string{"--classify"},
+			expected: []string{"--classify=always"},
 		},
 
 		// Symlinks
@@ -230,6 +232,36 @@ func TestTranslateFlagsGNU(t *testing.T) {
 			input:    []string{"--color=auto"},
 			expected: []string{"--color=auto"},
 		},
+		{
+			name:     "color without WHEN",
+			input:    []string{"--color"},
+			expected: []string{"--color=always"},
+		},
+		{
+			name:     "colour tty",
+			input:    []string{"--colour=tty"},
+			expected: []string{"--color=auto"},
+		},
+		{
+			name:     "color if-tty",
+			input:    []string{"--color=if-tty"},
+			expected: []string{"--color=auto"},
+		},
+		{
+			name:     "color yes",
+			input:    []string{"--color=yes"},
+			expected: []string{"--color=always"},
+		},
+		{
+			name:     "color force",
+			input:    []string{"--color=force"},
+			expected: []string{"--color=always"},
+		},
+		{
+			name:     "color none",
+			input:    []string{"--color=none"},
+			expected: []string{"--color=never"},
+		},
 		{
 			name:     "color always passthrough",
 			input:    []string{"--color=always"},
@@ -246,6 +278,108 @@ func TestTranslateFlagsGNU(t *testing.T) {
 			expected: []string{"--time=accessed"},
 		},
 
+		// GNU sort and time words
+		{
+			name:     "sort time adds reverse",
+			input:    []string{"-l", "--sort=time"},
+			expected: []string{"-l", "--sort=modified", "--reverse", "--bytes"},
+		},
+		{
+			name:     "sort word as a separate argument",
+			input:    []string{"--sort", "time"},
+			expected: []string{"--sort=modified", "--reverse"},
+		},
+		{
+			name:     "sort time with user reverse cancels",
+			input:    []string{"-lr", "--sort=time"},
+			expected: []string{"-l", "--sort=modified", "--bytes"},
+		},
+		{
+			name:     "sort size adds reverse",
+			input:    []string{"--sort=size"},
+			expected: []string{"--sort=size", "--reverse"},
+		},
+		{
+			name:     "sort size with long reverse cancels",
+			input:    []string{"--sort=size", "--reverse"},
+			expected: []string{"--sort=size"},
+		},
+		{
+			name:     "sort none",
+			input:    []string{"--sort=none"},
+			expected: []string{"--sort=none"},
+		},
+		{
+			name:     "sort version",
+			input:    []string{"--sort=version"},
+			expected: []string{"--sort=name"},
+		},
+		{
+			name:     "sort extension",
+			input:    []string{"--sort=extension"},
+			expected: []string{"--sort=extension"},
+		},
+		{
+			name:     "sort width has no equivalent",
+			input:    []string{"-l", "--sort=width"},
+			expected: []string{"-l", "--bytes"},
+		},
+		{
+			name:     "later sort name cancels time reverse",
+			input:    []string{"-t", "--sort=name"},
+			expected: []string{"--sort=modified", "--sort=name"},
+		},
+		{
+			name:     "time atime",
+			input:    []string{"-l", "--time=atime"},
+			expected: []string{"-l", "--time=accessed", "--bytes"},
+		},
+		{
+			name:     "time word as a separate argument",
+			input:    []string{"-l", "--time", "ctime"},
+			expected: []string{"-l", "--time=changed", "--bytes"},
+		},
+		{
+			name:     "separate sort and time words",
+			input:    []string{"-l", "--sort", "time", "--time", "ctime"},
+			expected: []string{"-l", "--sort=changed", "--time=changed", "--reverse", "--bytes"},
+		},
+		{
+			name:     "time use",
+			input:    []string{"--time=use"},
+			expected: []string{"--time=accessed"},
+		},
+		{
+			name:     "time ctime",
+			input:    []string{"--time=ctime"},
+			expected: []string{"--time=changed"},
+		},
+		{
+			name:     "time status",
+			input:    []string{"--time=status"},
+			expected: []string{"--time=changed"},
+		},
+		{
+			name:     "time birth",
+			input:    []string{"--time=birth"},
+			expected: []string{"--time=created"},
+		},
+		{
+			name:     "time mtime",
+			input:    []string{"--time=mtime"},
+			expected: []string{"--time=modified"},
+		},
+		{
+			name:     "sort time uses chosen timestamp",
+			input:    []string{"-l", "--sort=time", "--time=ctime"},
+			expected: []string{"-l", "--sort=changed", "--time=changed", "--reverse", "--bytes"},
+		},
+		{
+			name:     "t uses chosen timestamp",
+			input:    []string{"-lt", "--time=birth"},
+			expected: []string{"-l", "--sort=created", "--time=created", "--reverse", "--bytes"},
+		},
+
 		// Paths
 		{
 			name:     "single path",
@@ -260,19 +394,19 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "flags and paths",
 			input:    []string{"-la", "/tmp", "/var"},
-			expected: []string{"-l", "-a", "/tmp", "/var"},
+			expected: []string{"-l", "-a", "--bytes", "/tmp", "/var"},
 		},
 
 		// Deduplication
 		{
 			name:     "dedup repeated flags",
 			input:    []string{"-l", "-l"},
-			expected: []string{"-l"},
+			expected: []string{"-l", "--bytes"},
 		},
 		{
 			name:     "dedup from og combo",
 			input:    []string{"-og"},
-			expected: []string{"-l", "--no-group", "--no-user"},
+			expected: []string{"-l", "--no-group", "--no-user", "--bytes"},
 		},
 
 		// GNU ls specific flags
@@ -289,7 +423,7 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "GNU sort by extension",
 			input:    []string{"-lX"},
-			expected: []string{"-l", "--sort=extension"},
+			expected: []string{"-l", "--sort=extension", "--bytes"},
 		},
 		{
 			name:     "GNU width",
@@ -304,12 +438,12 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "GNU SELinux context",
 			input:    []string{"-lZ"},
-			expected: []string{"-l", "-Z"},
+			expected: []string{"-l", "-Z", "--bytes"},
 		},
 		{
 			name:     "GNU literal/no-quotes",
 			input:    []string{"-lN"},
-			expected: []string{"-l", "--no-quotes"},
+			expected: []string{"-l", "--no-quotes", "--bytes"},
 		},
 		{
 			name:     "GNU group directories first",
@@ -319,7 +453,7 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "GNU full-time",
 			input:    []string{"--full-time"},
-			expected: []string{"-l", "--time-style=full-iso"},
+			expected: []string{"-l", "--time-style=full-iso", "--bytes"},
 		},
 		{
 			name:     "GNU ignore long option",
@@ -336,12 +470,12 @@ func TestTranslateFlagsGNU(t *testing.T) {
 		{
 			name:     "ignored flag W",
 			input:    []string{"-lW"},
-			expected: []string{"-l"},
+			expected: []string{"-l", "--bytes"},
 		},
 		{
 			name:     "ignored flag Q",
 			input:    []string{"-lQ"},
-			expected: []string{"-l"},
+			expected: []string{"-l", "--bytes"},
 		},
 
 		// Edge cases
@@ -364,7 +498,7 @@ func TestTranslateFlagsGNU(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := translateFlags(tt.input, ModeGNU)
+			result := translateFlags(tt.input, ModeGNU, translator.Options{}).Args
 			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("translateFlags(%v, ModeGNU) = %v, want %v", tt.input, result, tt.expected)
 			}
@@ -397,23 +531,23 @@ func TestTranslateFlagsBSD(t *testing.T) {
 		{
 			name:     "BSD -I ignored",
 			input:    []string{"-lI"},
-			expected: []string{"-l"},
+			expected: []string{"-l", "--bytes"},
 		},
 		{
 			name:     "BSD -X ignored",
 			input:    []string{"-lX"},
-			expected: []string{"-l"},
+			expected: []string{"-l", "--bytes"},
 		},
 		{
 			name:     "BSD -w ignored",
 			input:    []string{"-lw"},
-			expected: []string{"-l"},
+			expected: []string{"-l", "--bytes"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := translateFlags(tt.input, ModeBSD)
+			result := translateFlags(tt.input, ModeBSD, translator.Options{}).Args
 			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("translateFlags(%v, ModeBSD) = %v, want %v", tt.input, result, tt.expected)
 			}
@@ -421,6 +555,582 @@ func TestTranslateFlagsBSD(t *testing.T) {
 	}
 }
 
+func TestTimeStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		mode     LSMode
+		env      map[string]string
+		expected []string
+	}{
+		{
+			name:     "full-iso",
+			input:    []string{"-l", "--time-style=full-iso"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--time-style=full-iso", "--bytes"},
+		},
+		{
+			name:     "long-iso separate value",
+			input:    []string{"-l", "--time-style", "long-iso"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--time-style=long-iso", "--bytes"},
+		},
+		{
+			name:     "locale approximated",
+			input:    []string{"-l", "--time-style=locale"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--time-style=default", "--bytes"},
+		},
+		{
+			name:     "posix style in POSIX locale",
+			input:    []string{"-l", "--time-style=posix-long-iso"},
+			mode:     ModeGNU,
+			env:      map[string]string{"LC_ALL": "C"},
+			expected: []string{"-l", "--time-style=default", "--bytes"},
+		},
+		{
+			name:     "posix style in other locale",
+			input:    []string{"-l", "--time-style=posix-long-iso"},
+			mode:     ModeGNU,
+			env:      map[string]string{"LANG": "en_US.UTF-8"},
+			expected: []string{"-l", "--time-style=long-iso", "--bytes"},
+		},
+		{
+			name:     "LC_TIME overrides LANG",
+			input:    []string{"-l", "--time-style=posix-iso"},
+			mode:     ModeGNU,
+			env:      map[string]string{"LC_TIME": "POSIX", "LANG": "de_DE.UTF-8"},
+			expected: []string{"-l", "--time-style=default", "--bytes"},
+		},
+		{
+			name:     "custom format",
+			input:    []string{"-l", "--time-style=+%Y-%m-%d %H:%M"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--time-style=+%Y-%m-%d %H:%M", "--bytes"},
+		},
+		{
+			name:     "two formats keep the first",
+			input:    []string{"-l", "--time-style=+%Y-%m-%d\n%m-%d %H:%M"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--time-style=+%Y-%m-%d", "--bytes"},
+		},
+		{
+			name:     "nanoseconds converted",
+			input:    []string{"-l", "--time-style=+%H:%M:%S.%N"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--time-style=+%H:%M:%S.%f", "--bytes"},
+		},
+		{
+			name:     "milliseconds and modifiers converted",
+			input:    []string{"-l", "--time-style=+%^b %-d %Ey %T.%3N"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--time-style=+%b %-d %y %T.%3f", "--bytes"},
+		},
+		{
+			name:     "TIME_STYLE from environment",
+			input:    []string{"-l"},
+			mode:     ModeGNU,
+			env:      map[string]string{"TIME_STYLE": "long-iso"},
+			expected: []string{"-l", "--time-style=long-iso", "--bytes"},
+		},
+		{
+			name:     "TIME_STYLE two formats",
+			input:    []string{"-l"},
+			mode:     ModeGNU,
+			env:      map[string]string{"TIME_STYLE": "+%d.%m.%Y\n%d.%m. %H:%M"},
+			expected: []string{"-l", "--time-style=+%d.%m.%Y", "--bytes"},
+		},
+		{
+			name:     "explicit style beats TIME_STYLE",
+			input:    []string{"-l", "--time-style=iso"},
+			mode:     ModeGNU,
+			env:      map[string]string{"TIME_STYLE": "long-iso"},
+			expected: []string{"-l", "--time-style=iso", "--bytes"},
+		},
+		{
+			name:     "full-time beats TIME_STYLE",
+			input:    []string{"--full-time"},
+			mode:     ModeGNU,
+			env:      map[string]string{"TIME_STYLE": "locale"},
+			expected: []string{"-l", "--time-style=full-iso", "--bytes"},
+		},
+		{
+			name:     "BSD ignores TIME_STYLE",
+			input:    []string{"-l"},
+			mode:     ModeBSD,
+			env:      map[string]string{"TIME_STYLE": "long-iso"},
+			expected: []string{"-l", "--bytes"},
+		},
+		{
+			name:     "BSD D format converted",
+			input:    []string{"-l", "-D", "%Ey-%m-%d"},
+			mode:     ModeBSD,
+			expected: []string{"-l", "--time-style=+%y-%m-%d", "--bytes"},
+		},
+		{
+			name:     "BSD T full timestamp",
+			input:    []string{"-lT"},
+			mode:     ModeBSD,
+			expected: []string{"-l", "--time-style=full-iso", "--bytes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.mode, translator.Options{Env: tt.env}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSizeFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		mode     LSMode
+		env      map[string]string
+		expected []string
+	}{
+		{
+			name:     "long view shows bytes",
+			input:    []string{"-l"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--bytes"},
+		},
+		{
+			name:     "short view has no size flag",
+			input:    []string{"-a"},
+			mode:     ModeGNU,
+			expected: []string{"-a"},
+		},
+		{
+			name:     "human-readable",
+			input:    []string{"-l", "--human-readable"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--binary"},
+		},
+		{
+			name:     "si is eza default",
+			input:    []string{"-l", "--si"},
+			mode:     ModeGNU,
+			expected: []string{"-l"},
+		},
+		{
+			name:     "last size option wins",
+			input:    []string{"-lh", "--si"},
+			mode:     ModeGNU,
+			expected: []string{"-l"},
+		},
+		{
+			name:     "kibibytes keeps bytes",
+			input:    []string{"-lk"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--bytes"},
+		},
+		{
+			name:     "block size 1",
+			input:    []string{"-lh", "--block-size=1"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--bytes"},
+		},
+		{
+			name:     "block size K",
+			input:    []string{"-l", "--block-size", "K"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--binary"},
+		},
+		{
+			name:     "block size MB",
+			input:    []string{"-l", "--block-size=MB"},
+			mode:     ModeGNU,
+			expected: []string{"-l"},
+		},
+		{
+			name:     "block size with separators",
+			input:    []string{"-l", "--block-size='1"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "--bytes"},
+		},
+		{
+			name:     "LS_BLOCK_SIZE",
+			input:    []string{"-l"},
+			mode:     ModeGNU,
+			env:      map[string]string{"LS_BLOCK_SIZE": "human-readable", "BLOCK_SIZE": "1"},
+			expected: []string{"-l", "--binary"},
+		},
+		{
+			name:     "BLOCK_SIZE",
+			input:    []string{"-l"},
+			mode:     ModeGNU,
+			env:      map[string]string{"BLOCK_SIZE": "si"},
+			expected: []string{"-l"},
+		},
+		{
+			name:     "flag overrides environment",
+			input:    []string{"-lh"},
+			mode:     ModeGNU,
+			env:      map[string]string{"BLOCK_SIZE": "1"},
+			expected: []string{"-l", "--binary"},
+		},
+		{
+			name:     "BSD ignores BLOCK_SIZE",
+			input:    []string{"-l"},
+			mode:     ModeBSD,
+			env:      map[string]string{"BLOCK_SIZE": "human-readable"},
+			expected: []string{"-l", "--bytes"},
+		},
+		{
+			name:     "BSD human-readable",
+			input:    []string{"-lh"},
+			mode:     ModeBSD,
+			expected: []string{"-l", "--binary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.mode, translator.Options{Env: tt.env}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIndicatorAndQuotingStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		mode     LSMode
+		env      map[string]string
+		expected []string
+		warns    bool
+	}{
+		{
+			name:     "classify",
+			input:    []string{"--classify"},
+			mode:     ModeGNU,
+			expected: []string{"--classify=always"},
+		},
+		{
+			name:     "classify auto",
+			input:    []string{"--classify=if-tty"},
+			mode:     ModeGNU,
+			expected: []string{"--classify=auto"},
+		},
+		{
+			name:     "classify never",
+			input:    []string{"--classify=never"},
+			mode:     ModeGNU,
+			expected: []string{"--classify=never"},
+		},
+		{
+			name:     "slash warns",
+			input:    []string{"-p"},
+			mode:     ModeBSD,
+			expected: []string{"--classify=always"},
+			warns:    true,
+		},
+		{
+			name:     "file-type warns",
+			input:    []string{"--file-type"},
+			mode:     ModeGNU,
+			expected: []string{"--classify=always"},
+			warns:    true,
+		},
+		{
+			name:     "indicator-style classify",
+			input:    []string{"--indicator-style=classify"},
+			mode:     ModeGNU,
+			expected: []string{"--classify=always"},
+		},
+		{
+			name:     "indicator-style separate value",
+			input:    []string{"--indicator-style", "slash", "/tmp"},
+			mode:     ModeGNU,
+			expected: []string{"--classify=always", "/tmp"},
+			warns:    true,
+		},
+		{
+			name:     "indicator-style none overrides F",
+			input:    []string{"-F", "--indicator-style=none"},
+			mode:     ModeGNU,
+			expected: nil,
+		},
+		{
+			name:     "literal",
+			input:    []string{"--quoting-style=literal"},
+			mode:     ModeGNU,
+			expected: []string{"--no-quotes"},
+		},
+		{
+			name:     "shell-escape is eza default",
+			input:    []string{"--quoting-style=shell-escape"},
+			mode:     ModeGNU,
+			expected: nil,
+		},
+		{
+			name:     "shell-always warns",
+			input:    []string{"--quoting-style", "shell-always"},
+			mode:     ModeGNU,
+			expected: nil,
+			warns:    true,
+		},
+		{
+			name:     "Q warns",
+			input:    []string{"-Q"},
+			mode:     ModeGNU,
+			expected: nil,
+			warns:    true,
+		},
+		{
+			name:     "escape",
+			input:    []string{"-b"},
+			mode:     ModeGNU,
+			expected: []string{"--no-quotes"},
+			warns:    true,
+		},
+		{
+			name:     "last quoting style wins",
+			input:    []string{"-Q", "-N"},
+			mode:     ModeGNU,
+			expected: []string{"--no-quotes"},
+		},
+		{
+			name:     "QUOTING_STYLE",
+			input:    []string{"-l"},
+			mode:     ModeGNU,
+			env:      map[string]string{"QUOTING_STYLE": "literal"},
+			expected: []string{"-l", "--no-quotes", "--bytes"},
+		},
+		{
+			name:     "flag overrides QUOTING_STYLE",
+			input:    []string{"--quoting-style=shell"},
+			mode:     ModeGNU,
+			env:      map[string]string{"QUOTING_STYLE": "c"},
+			expected: nil,
+		},
+		{
+			name:     "BSD ignores QUOTING_STYLE",
+			input:    []string{"-a"},
+			mode:     ModeBSD,
+			env:      map[string]string{"QUOTING_STYLE": "literal"},
+			expected: []string{"-a"},
+		},
+		{
+			name:     "hide control chars warns",
+			input:    []string{"-q"},
+			mode:     ModeBSD,
+			expected: nil,
+			warns:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.mode, translator.Options{Env: tt.env})
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+		})
+	}
+}
+
+func TestTranslateLSColors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "BSD default",
+			input:    "exfxcxdxbxegedabagacad",
+			expected: "di=34:ln=35:so=32:pi=33:ex=31:bd=34;46:cd=34;43:su=30;41:sg=30;46:tw=30;42:ow=30;43",
+		},
+		{
+			name:     "bold and default colors",
+			input:    "ExGxxxxxCx",
+			expected: "di=1;34:ln=1;36:ex=1;32",
+		},
+		{
+			name:     "background only",
+			input:    "xb",
+			expected: "di=41",
+		},
+		{
+			name:     "all default",
+			input:    "xxxx",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TranslateLSColors(tt.input); got != tt.expected {
+				t.Errorf("TranslateLSColors(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestColorEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []string
+		mode        LSMode
+		env         map[string]string
+		expected    []string
+		expectedEnv map[string]string
+	}{
+		{
+			name:        "LSCOLORS becomes EZA_COLORS",
+			input:       []string{"-G"},
+			mode:        ModeBSD,
+			env:         map[string]string{"LSCOLORS": "Gxfx"},
+			expected:    nil,
+			expectedEnv: map[string]string{"EZA_COLORS": "di=1;36:ln=35"},
+		},
+		{
+			name:     "EZA_COLORS takes precedence",
+			input:    []string{"-G"},
+			mode:     ModeBSD,
+			env:      map[string]string{"LSCOLORS": "Gxfx", "EZA_COLORS": "di=32"},
+			expected: nil,
+		},
+		{
+			name:     "LS_COLORS takes precedence",
+			input:    []string{"-G"},
+			mode:     ModeBSD,
+			env:      map[string]string{"LSCOLORS": "Gxfx", "LS_COLORS": "di=32"},
+			expected: nil,
+		},
+		{
+			name:     "GNU ignores LSCOLORS",
+			input:    []string{"-a"},
+			mode:     ModeGNU,
+			env:      map[string]string{"LSCOLORS": "Gxfx"},
+			expected: []string{"-a"},
+		},
+		{
+			name:     "CLICOLOR_FORCE",
+			input:    []string{"-G"},
+			mode:     ModeBSD,
+			env:      map[string]string{"CLICOLOR": "1", "CLICOLOR_FORCE": "1"},
+			expected: []string{"--color=always"},
+		},
+		{
+			name:     "color flag overrides CLICOLOR_FORCE",
+			input:    []string{"--color=never"},
+			mode:     ModeBSD,
+			env:      map[string]string{"CLICOLOR_FORCE": "1"},
+			expected: []string{"--color=never"},
+		},
+		{
+			name:     "GNU ignores CLICOLOR_FORCE",
+			input:    []string{"-a"},
+			mode:     ModeGNU,
+			env:      map[string]string{"CLICOLOR_FORCE": "1"},
+			expected: []string{"-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.mode, translator.Options{Env: tt.env})
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if !reflect.DeepEqual(result.Env, tt.expectedEnv) {
+				t.Errorf("translateFlags(%v) env = %v, want %v", tt.input, result.Env, tt.expectedEnv)
+			}
+		})
+	}
+}
+
+func TestEndOfOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		mode     LSMode
+		expected []string
+	}{
+		{
+			name:     "dash-prefixed file",
+			input:    []string{"-la", "--", "-weird-file"},
+			mode:     ModeGNU,
+			expected: []string{"-l", "-a", "--bytes", "--", "-weird-file"},
+		},
+		{
+			name:     "flags after -- are files",
+			input:    []string{"--", "-l", "--all"},
+			mode:     ModeGNU,
+			expected: []string{"--", "-l", "--all"},
+		},
+		{
+			name:     "paths before and after --",
+			input:    []string{"/tmp", "-a", "--", "-x"},
+			mode:     ModeGNU,
+			expected: []string{"-a", "--", "/tmp", "-x"},
+		},
+		{
+			name:     "second -- is a file",
+			input:    []string{"--", "--"},
+			mode:     ModeGNU,
+			expected: []string{"--", "--"},
+		},
+		{
+			name:     "-- without files",
+			input:    []string{"-a", "--"},
+			mode:     ModeGNU,
+			expected: []string{"-a"},
+		},
+		{
+			name:     "GNU -I value can start with a dash",
+			input:    []string{"-aI", "-*", "--", "-f"},
+			mode:     ModeGNU,
+			expected: []string{"-a", "--ignore-glob=-*", "--", "-f"},
+		},
+		{
+			name:     "GNU -I consumes --",
+			input:    []string{"-I", "--", "-a"},
+			mode:     ModeGNU,
+			expected: []string{"--ignore-glob=--", "-a"},
+		},
+		{
+			name:     "GNU -w with separate value",
+			input:    []string{"-w", "80", "--", "-w"},
+			mode:     ModeGNU,
+			expected: []string{"--width=80", "--", "-w"},
+		},
+		{
+			name:     "BSD -D value can start with a dash",
+			input:    []string{"-lD", "-%d", "--", "-l"},
+			mode:     ModeBSD,
+			expected: []string{"-l", "--time-style=+-%d", "--bytes", "--", "-l"},
+		},
+		{
+			name:     "BSD -I and -w are plain flags",
+			input:    []string{"-Iw", "--", "-I"},
+			mode:     ModeBSD,
+			expected: []string{"--", "-I"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.mode, translator.Options{}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestVersionFlag(t *testing.T) {
 	// -V and --version should not be translated, they're handled in main()
 	// But if they somehow get to translateFlags, they should pass through
@@ -433,7 +1143,7 @@ func TestVersionFlag(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result := translateFlags(tt.input, ModeGNU)
+		result := translateFlags(tt.input, ModeGNU, translator.Options{}).Args
 		if !reflect.DeepEqual(result, tt.expected) {
 			t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
 		}
@@ -456,9 +1166,9 @@ func TestGetLSModeParameter(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getLSMode(tt.mode)
+			result := GetLSMode(tt.mode)
 			if result != tt.expected {
-				t.Errorf("getLSMode(%q) = %v, want %v", tt.mode, result, tt.expected)
+				t.Errorf("GetLSMode(%q) = %v, want %v", tt.mode, result, tt.expected)
 			}
 		})
 	}
@@ -478,8 +1188,8 @@ func TestTranslatorInterface(t *testing.T) {
 	}
 
 	// Test translation via interface
-	result := tr.Translate([]string{"-la"}, "")
-	expected := []string{"-l", "-a"}
+	result := tr.Translate([]string{"-la"}, translator.Options{}).Args
+	expected := []string{"-l", "-a", "--bytes"}
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("Translate(-la) = %v, want %v", result, expected)
 	}
@@ -0,0 +1,418 @@
+package ls2lsd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ls2eza"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the ls to lsd flag translation
+type Translator struct{}
+
+func (t *Translator) Name() string        { return "ls2lsd" }
+func (t *Translator) SourceTool() string  { return "ls" }
+func (t *Translator) TargetTool() string  { return "lsd" }
+func (t *Translator) IncludeInInit() bool { return false }
+
+// Translate converts ls arguments to lsd arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translateFlags(args, ls2eza.GetLSMode(opts.Mode), opts)
+}
+
+// Simple 1:1 flag mappings
+// lsd sorts like ls (newest and largest first), so no --reverse juggling is needed
+var flagMap = map[rune][]string{
+	// Display format
+	'l': {"-l"}, // long format
+	'1': {"-1"}, // one entry per line
+	'C': {},     // multi-column output (lsd default)
+	'x': {},     // sort grid across (no lsd equivalent)
+	'm': {},     // stream output (no lsd equivalent)
+
+	// Show/hide entries
+	'a': {"-a"}, // show all including . and ..
+	'A': {"-A"}, // show hidden but not . and ..
+	'd': {"-d"}, // list directories themselves
+	'R': {"-R"}, // recurse into directories
+
+	// Sorting
+	't': {"-t"},       // sort by modification time
+	'S': {"-S"},       // sort by size
+	'f': {"-a", "-U"}, // unsorted, show all
+	'v': {"-v"},       // natural version sort
+	'r': {"-r"},       // reverse sort order
+
+	// File size display
+	'k': {}, // 1024-byte blocks (only affects -s and totals)
+	's': {}, // show allocated blocks (no lsd equivalent)
+
+	// Indicators and quoting
+	'F': {"-F"}, // append file type indicators
+	'N': {"-N"}, // print names without quoting
+
+	// Long format options
+	'i': {"-i"}, // show inode numbers
+	'n': {},     // numeric user/group IDs (no lsd equivalent)
+	'O': {},     // show file flags (no lsd equivalent)
+	'e': {},     // show ACL (no lsd equivalent)
+	'@': {},     // show extended attributes (no lsd equivalent)
+
+	// Symlink handling
+	'L': {"-L"}, // dereference symlinks
+	'H': {"-L"}, // follow symlinks on command line
+	'P': {},     // don't follow symlinks (default)
+
+	// Color
+	'G': {}, // color output (default in lsd)
+
+	// Misc
+	'q': {},     // replace non-printable with ?
+	'b': {},     // C-style escapes
+	'B': {},     // octal escapes (BSD) / ignore-backups (GNU)
+	'W': {},     // display whiteouts (BSD)
+	'Q': {},     // quote names
+	'Z': {"-Z"}, // SELinux security context
+}
+
+// Long option mappings
+var longFlagMap = map[string][]string{
+	"--all":             {"-a"},
+	"--almost-all":      {"-A"},
+	"--directory":       {"-d"},
+	"--recursive":       {"-R"},
+	"--reverse":         {"-r"},
+	"--inode":           {"-i"},
+	"--file-type":       {"-F"},
+	"--dereference":     {"-L"},
+	"--literal":         {"-N"},
+	"--context":         {"-Z"},
+	"--numeric-uid-gid": {},
+
+	"--group-directories-first": {"--group-dirs=first"},
+	"--size":                    {},
+	"--quote-name":              {},
+	"--hide-control-chars":      {},
+	"--show-control-chars":      {},
+	"--hyperlink":               {"--hyperlink=always"},
+	"--author":                  {},
+	"--escape":                  {},
+	"--ignore-backups":          {},
+	"--kibibytes":               {},
+	"--dired":                   {},
+	"--zero":                    {},
+}
+
+// GNU ls --sort=WORD values and their lsd --sort equivalents
+// An empty mapping means lsd's default (name) or no equivalent
+var sortWordMap = map[string]string{
+	"none":      "none",
+	"name":      "",
+	"size":      "size",
+	"time":      "time",
+	"version":   "version",
+	"extension": "extension",
+	"width":     "",
+}
+
+// Long format columns in lsd's default order
+var defaultBlocks = []string{"permission", "user", "group", "size", "date", "name"}
+
+// translateDate converts an ls time style to an lsd --date value
+// Returns "" if the style can't be expressed
+func translateDate(style string, opts translator.Options) string {
+	if style == "locale" {
+		return "locale"
+	}
+	switch mapped := ls2eza.TranslateTimeStyle(style, opts); mapped {
+	case "full-iso":
+		return "+%Y-%m-%d %H:%M:%S.%f %z"
+	case "long-iso":
+		return "+%Y-%m-%d %H:%M"
+	case "iso":
+		return "+%m-%d %H:%M"
+	case "default":
+		return "date"
+	case "relative":
+		return "relative"
+	default:
+		if strings.HasPrefix(mapped, "+") {
+			return mapped
+		}
+		return ""
+	}
+}
+
+func translateFlags(args []string, mode ls2eza.LSMode, opts translator.Options) translator.Result {
+	var lsdArgs []string
+	var paths []string
+	var warnings []string
+	skipNext := false
+	endOfOptions := false
+	sizeFormat := ls2eza.SizeBytes
+	date := ""
+	color := ""
+	noUser := false
+	noGroup := false
+
+	if mode == ls2eza.ModeGNU {
+		// LS_BLOCK_SIZE takes precedence over BLOCK_SIZE
+		if size := opts.Getenv("LS_BLOCK_SIZE"); size != "" {
+			sizeFormat = ls2eza.BlockSizeFormat(size)
+		} else if size := opts.Getenv("BLOCK_SIZE"); size != "" {
+			sizeFormat = ls2eza.BlockSizeFormat(size)
+		}
+		if style := opts.Getenv("TIME_STYLE"); style != "" {
+			date = translateDate(style, opts)
+		}
+	}
+
+	warn := func(msg string) {
+		if !slices.Contains(warnings, msg) {
+			warnings = append(warnings, msg)
+		}
+	}
+
+	for i, arg := range args {
+		if skipNext {
+			skipNext = false
+			continue
+		}
+
+		// Everything after -- is a file, even if it starts with a dash
+		if endOfOptions {
+			paths = append(paths, arg)
+			continue
+		}
+		if arg == "--" {
+			endOfOptions = true
+			continue
+		}
+
+		if strings.HasPrefix(arg, "--") {
+			// Options that can take their value as a separate argument
+			switch arg {
+			case "--sort", "--time", "--time-style", "--block-size", "--indicator-style", "--quoting-style", "--width", "--ignore", "--hide", "--tabsize", "--format":
+				if i+1 < len(args) {
+					arg += "=" + args[i+1]
+					skipNext = true
+				}
+			}
+
+			name, value, hasValue := strings.Cut(arg, "=")
+			switch name {
+			case "--human-readable":
+				sizeFormat = ls2eza.SizeBinary
+			case "--si":
+				sizeFormat = ls2eza.SizeSI
+			case "--block-size":
+				sizeFormat = ls2eza.BlockSizeFormat(value)
+			case "--sort":
+				mapped, known := sortWordMap[value]
+				if !known {
+					mapped = value
+				}
+				if mapped != "" {
+					lsdArgs = append(lsdArgs, "--sort="+mapped)
+				}
+			case "--time":
+				warn("lsd always shows the modification time")
+			case "--time-style":
+				date = translateDate(value, opts)
+			case "--full-time":
+				lsdArgs = append(lsdArgs, "-l")
+				date = translateDate("full-iso", opts)
+			case "--color", "--colour":
+				color = ls2eza.TranslateWhen(value)
+			case "--classify":
+				if ls2eza.TranslateWhen(value) != "never" {
+					lsdArgs = append(lsdArgs, "-F")
+				}
+			case "--indicator-style":
+				if value == "classify" || value == "slash" || value == "file-type" {
+					lsdArgs = append(lsdArgs, "-F")
+				}
+			case "--quoting-style":
+				if value == "literal" {
+					lsdArgs = append(lsdArgs, "-N")
+				}
+			case "--hyperlink":
+				lsdArgs = append(lsdArgs, "--hyperlink="+ls2eza.TranslateWhen(value))
+			case "--ignore":
+				lsdArgs = append(lsdArgs, "--ignore-glob="+value)
+			case "--no-group":
+				noGroup = true
+			case "--format":
+				switch value {
+				case "long", "verbose":
+					lsdArgs = append(lsdArgs, "-l")
+				case "single-column":
+					lsdArgs = append(lsdArgs, "-1")
+				}
+			case "--width", "--hide", "--tabsize":
+				// No lsd equivalent
+			default:
+				if hasValue {
+					// Unknown option with a value, lsd would reject it
+					continue
+				}
+				if mapped, ok := longFlagMap[arg]; ok {
+					lsdArgs = append(lsdArgs, mapped...)
+				} else {
+					lsdArgs = append(lsdArgs, arg)
+				}
+			}
+		} else if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+			flags := arg[1:]
+			for j, c := range flags {
+				if c == 'h' {
+					sizeFormat = ls2eza.SizeBinary
+					continue
+				}
+				if c == 'o' || c == 'g' {
+					lsdArgs = append(lsdArgs, "-l")
+					if c == 'o' {
+						noGroup = true
+					} else {
+						noUser = true
+					}
+					continue
+				}
+				if c == 'p' {
+					lsdArgs = append(lsdArgs, "-F")
+					warn("lsd can't mark only directories, -F also marks executables, links, pipes and sockets")
+					continue
+				}
+				if c == 'c' || c == 'u' {
+					warn("lsd can't show or sort by change and access times")
+					continue
+				}
+				if c == 'U' {
+					if mode == ls2eza.ModeGNU {
+						lsdArgs = append(lsdArgs, "-U")
+					} else {
+						warn("lsd can't sort by creation time")
+					}
+					continue
+				}
+				if c == 'X' {
+					if mode == ls2eza.ModeGNU {
+						lsdArgs = append(lsdArgs, "-X")
+					}
+					continue
+				}
+				if c == 'T' {
+					if mode == ls2eza.ModeBSD {
+						date = translateDate("full-iso", opts)
+						continue
+					}
+					// GNU -T takes a tab size
+					if len(flags[j+1:]) > 0 {
+						break
+					} else if i+1 < len(args) {
+						skipNext = true
+					}
+					continue
+				}
+				if c == 'D' || c == 'I' || c == 'w' {
+					valueFlag := (c == 'D' && mode == ls2eza.ModeBSD) || (c != 'D' && mode == ls2eza.ModeGNU)
+					if !valueFlag {
+						continue
+					}
+					value := string(flags[j+1:])
+					if value == "" && i+1 < len(args) {
+						value = args[i+1]
+						skipNext = true
+					}
+					if value != "" && c == 'D' {
+						date = translateDate("+"+value, opts)
+					} else if value != "" && c == 'I' {
+						lsdArgs = append(lsdArgs, "--ignore-glob="+value)
+					}
+					// -w: lsd has no width option
+					break
+				}
+				if mapped, ok := flagMap[c]; ok {
+					lsdArgs = append(lsdArgs, mapped...)
+				} else {
+					lsdArgs = append(lsdArgs, "-"+string(c))
+				}
+			}
+		} else {
+			paths = append(paths, arg)
+		}
+	}
+
+	long := slices.Contains(lsdArgs, "-l")
+
+	if date != "" && long {
+		lsdArgs = append(lsdArgs, "--date="+date)
+	}
+
+	if long {
+		switch sizeFormat {
+		case ls2eza.SizeBytes:
+			lsdArgs = append(lsdArgs, "--size=bytes")
+		case ls2eza.SizeSI:
+			warn("lsd can't show sizes in powers of 1000")
+		}
+	}
+
+	// -o, -g and --no-group drop the owner and group columns
+	if long && (noUser || noGroup) {
+		var blocks []string
+		for _, b := range defaultBlocks {
+			if (b == "user" && noUser) || (b == "group" && noGroup) {
+				continue
+			}
+			blocks = append(blocks, b)
+		}
+		if slices.Contains(lsdArgs, "-i") {
+			blocks = append([]string{"inode"}, blocks...)
+		}
+		lsdArgs = append(lsdArgs, "--blocks="+strings.Join(blocks, ","))
+	}
+
+	// BSD ls colors even when piped if CLICOLOR_FORCE is set
+	if color == "" && mode == ls2eza.ModeBSD && opts.Getenv("CLICOLOR_FORCE") != "" {
+		color = "always"
+	}
+	if color != "" {
+		lsdArgs = append(lsdArgs, "--color="+color)
+	}
+
+	// lsd reads LS_COLORS but not BSD's LSCOLORS, and an LS_COLORS the user
+	// set takes precedence
+	var env map[string]string
+	if lscolors := opts.Getenv("LSCOLORS"); lscolors != "" && mode == ls2eza.ModeBSD && opts.Getenv("LS_COLORS") == "" {
+		if colors := ls2eza.TranslateLSColors(lscolors); colors != "" {
+			env = map[string]string{"LS_COLORS": colors}
+		}
+	}
+
+	// Plain ls-like output (no icons) when piped
+	if opts.Piped {
+		lsdArgs = append(lsdArgs, "--classic")
+	}
+
+	seen := make(map[string]bool)
+	var deduped []string
+	for _, f := range lsdArgs {
+		if !seen[f] {
+			seen[f] = true
+			deduped = append(deduped, f)
+		}
+	}
+
+	// Keep files that look like flags away from lsd's option parser
+	if endOfOptions && len(paths) > 0 {
+		deduped = append(deduped, "--")
+	}
+
+	return translator.Result{Args: append(deduped, paths...), Env: env, Warnings: warnings}
+}
@@ -0,0 +1,336 @@
+package ls2lsd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ls2eza"
+)
+
+func TestTranslateFlagsGNU(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "long format shows bytes",
+			input:    []string{"-l"},
+			expected: []string{"-l", "--size=bytes"},
+		},
+		{
+			name:     "combined la",
+			input:    []string{"-la"},
+			expected: []string{"-l", "-a", "--size=bytes"},
+		},
+		{
+			name:     "human-readable is lsd default",
+			input:    []string{"-lh"},
+			expected: []string{"-l"},
+		},
+		{
+			name:     "block size 1",
+			input:    []string{"-lh", "--block-size=1"},
+			expected: []string{"-l", "--size=bytes"},
+		},
+		{
+			name:     "time sort needs no reverse",
+			input:    []string{"-lt"},
+			expected: []string{"-l", "-t", "--size=bytes"},
+		},
+		{
+			name:     "reversed size sort",
+			input:    []string{"-Sr", "/tmp"},
+			expected: []string{"-S", "-r", "/tmp"},
+		},
+		{
+			name:     "sort words",
+			input:    []string{"--sort=time", "--sort", "version"},
+			expected: []string{"--sort=time", "--sort=version"},
+		},
+		{
+			name:     "sort by name is lsd default",
+			input:    []string{"--sort=name"},
+			expected: nil,
+		},
+		{
+			name:     "extension sort",
+			input:    []string{"-X"},
+			expected: []string{"-X"},
+		},
+		{
+			name:     "unsorted",
+			input:    []string{"-U"},
+			expected: []string{"-U"},
+		},
+		{
+			name:     "f shows all unsorted",
+			input:    []string{"-f"},
+			expected: []string{"-a", "-U"},
+		},
+		{
+			name:     "recursive",
+			input:    []string{"-R", "--recursive"},
+			expected: []string{"-R"},
+		},
+		{
+			name:     "no owner",
+			input:    []string{"-g"},
+			expected: []string{"-l", "--size=bytes", "--blocks=permission,group,size,date,name"},
+		},
+		{
+			name:     "no owner or group with inode",
+			input:    []string{"-ogi"},
+			expected: []string{"-l", "-i", "--size=bytes", "--blocks=inode,permission,size,date,name"},
+		},
+		{
+			name:     "no-group only affects the long view",
+			input:    []string{"--no-group"},
+			expected: nil,
+		},
+		{
+			name:     "full-time",
+			input:    []string{"--full-time"},
+			expected: []string{"-l", "--date=+%Y-%m-%d %H:%M:%S.%f %z", "--size=bytes"},
+		},
+		{
+			name:     "long-iso",
+			input:    []string{"-l", "--time-style", "long-iso"},
+			expected: []string{"-l", "--date=+%Y-%m-%d %H:%M", "--size=bytes"},
+		},
+		{
+			name:     "locale",
+			input:    []string{"-l", "--time-style=locale"},
+			expected: []string{"-l", "--date=locale", "--size=bytes"},
+		},
+		{
+			name:     "custom format",
+			input:    []string{"-l", "--time-style=+%d.%m.%Y %T.%3N"},
+			expected: []string{"-l", "--date=+%d.%m.%Y %T.%3f", "--size=bytes"},
+		},
+		{
+			name:     "time style without long view",
+			input:    []string{"--time-style=iso"},
+			expected: nil,
+		},
+		{
+			name:     "color words",
+			input:    []string{"--colour=if-tty"},
+			expected: []string{"--color=auto"},
+		},
+		{
+			name:     "color without WHEN",
+			input:    []string{"--color"},
+			expected: []string{"--color=always"},
+		},
+		{
+			name:     "classify",
+			input:    []string{"-F", "--classify=always"},
+			expected: []string{"-F"},
+		},
+		{
+			name:     "classify never",
+			input:    []string{"--classify=never"},
+			expected: nil,
+		},
+		{
+			name:     "literal",
+			input:    []string{"--quoting-style=literal"},
+			expected: []string{"-N"},
+		},
+		{
+			name:     "ignore patterns",
+			input:    []string{"-I", "*.o", "--ignore=*.a"},
+			expected: []string{"--ignore-glob=*.o", "--ignore-glob=*.a"},
+		},
+		{
+			name:     "width is dropped",
+			input:    []string{"-w80", "--width", "100"},
+			expected: nil,
+		},
+		{
+			name:     "group directories first",
+			input:    []string{"--group-directories-first"},
+			expected: []string{"--group-dirs=first"},
+		},
+		{
+			name:     "format long",
+			input:    []string{"--format=long"},
+			expected: []string{"-l", "--size=bytes"},
+		},
+		{
+			name:     "end of options",
+			input:    []string{"-a", "--", "-l"},
+			expected: []string{"-a", "--", "-l"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, ls2eza.ModeGNU, translator.Options{}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v, ModeGNU) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslateFlagsBSD(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "T is full time",
+			input:    []string{"-lT"},
+			expected: []string{"-l", "--date=+%Y-%m-%d %H:%M:%S.%f %z", "--size=bytes"},
+		},
+		{
+			name:     "D is a time format",
+			input:    []string{"-lD", "%Y"},
+			expected: []string{"-l", "--date=+%Y", "--size=bytes"},
+		},
+		{
+			name:     "U is creation time sort",
+			input:    []string{"-U"},
+			expected: nil,
+		},
+		{
+			name:     "X is not extension sort",
+			input:    []string{"-X"},
+			expected: nil,
+		},
+		{
+			name:     "I and w are plain flags",
+			input:    []string{"-Iw", "/tmp"},
+			expected: []string{"/tmp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, ls2eza.ModeBSD, translator.Options{}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v, ModeBSD) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnvironmentAndWarnings(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []string
+		mode        ls2eza.LSMode
+		opts        translator.Options
+		expected    []string
+		expectedEnv map[string]string
+		warns       bool
+	}{
+		{
+			name:     "TIME_STYLE",
+			input:    []string{"-l"},
+			mode:     ls2eza.ModeGNU,
+			opts:     translator.Options{Env: map[string]string{"TIME_STYLE": "long-iso"}},
+			expected: []string{"-l", "--date=+%Y-%m-%d %H:%M", "--size=bytes"},
+		},
+		{
+			name:     "BLOCK_SIZE",
+			input:    []string{"-l"},
+			mode:     ls2eza.ModeGNU,
+			opts:     translator.Options{Env: map[string]string{"BLOCK_SIZE": "human-readable"}},
+			expected: []string{"-l"},
+		},
+		{
+			name:        "LSCOLORS becomes LS_COLORS",
+			input:       []string{"-G"},
+			mode:        ls2eza.ModeBSD,
+			opts:        translator.Options{Env: map[string]string{"LSCOLORS": "Exfx"}},
+			expected:    nil,
+			expectedEnv: map[string]string{"LS_COLORS": "di=1;34:ln=35"},
+		},
+		{
+			name:     "LS_COLORS takes precedence over LSCOLORS",
+			input:    []string{"-G"},
+			mode:     ls2eza.ModeBSD,
+			opts:     translator.Options{Env: map[string]string{"LSCOLORS": "Exfx", "LS_COLORS": "di=1;32"}},
+			expected: nil,
+		},
+		{
+			name:     "CLICOLOR_FORCE",
+			input:    []string{"-G"},
+			mode:     ls2eza.ModeBSD,
+			opts:     translator.Options{Env: map[string]string{"CLICOLOR_FORCE": "1"}},
+			expected: []string{"--color=always"},
+		},
+		{
+			name:     "classic output when piped",
+			input:    []string{"-1"},
+			mode:     ls2eza.ModeGNU,
+			opts:     translator.Options{Piped: true},
+			expected: []string{"-1", "--classic"},
+		},
+		{
+			name:     "slash indicator warns",
+			input:    []string{"-p"},
+			mode:     ls2eza.ModeBSD,
+			expected: []string{"-F"},
+			warns:    true,
+		},
+		{
+			name:     "access time warns",
+			input:    []string{"-lu"},
+			mode:     ls2eza.ModeGNU,
+			expected: []string{"-l", "--size=bytes"},
+			warns:    true,
+		},
+		{
+			name:     "si warns",
+			input:    []string{"-l", "--si"},
+			mode:     ls2eza.ModeGNU,
+			expected: []string{"-l"},
+			warns:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.mode, tt.opts)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if !reflect.DeepEqual(result.Env, tt.expectedEnv) {
+				t.Errorf("translateFlags(%v) env = %v, want %v", tt.input, result.Env, tt.expectedEnv)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+		})
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "ls2lsd" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "ls2lsd")
+	}
+	if tr.SourceTool() != "ls" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "ls")
+	}
+	if tr.TargetTool() != "lsd" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "lsd")
+	}
+	if tr.IncludeInInit() {
+		t.Error("IncludeInInit() = true, want false")
+	}
+
+	// Test translation via interface
+	result := tr.Translate([]string{"-la"}, translator.Options{}).Args
+	expected := []string{"-l", "-a", "--size=bytes"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Translate(-la) = %v, want %v", result, expected)
+	}
+}
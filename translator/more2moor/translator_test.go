@@ -3,6 +3,8 @@ package more2moor
 import (
 	"reflect"
 	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
 )
 
 func TestTranslateFlags(t *testing.T) {
@@ -237,7 +239,7 @@ func TestTranslator(t *testing.T) {
 	// Test Translate method
 	input := []string{"-e", "file.txt"}
 	expected := []string{"--quit-if-one-screen", "file.txt"}
-	result := tr.Translate(input, "")
+	result := tr.Translate(input, translator.Options{}).Args
 
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("Translate(%v) = %v, want %v", input, result, expected)
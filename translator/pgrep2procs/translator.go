@@ -0,0 +1,211 @@
+package pgrep2procs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ps2procs"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the pgrep to procs flag translation
+// It shows the matching processes in a procs table instead of bare PIDs
+type Translator struct{}
+
+func (t *Translator) Name() string       { return "pgrep2procs" }
+func (t *Translator) SourceTool() string { return "pgrep" }
+func (t *Translator) TargetTool() string { return "procs" }
+
+// IncludeInInit is false since scripts in interactive shells rely on pgrep's
+// exit status, which procs doesn't set
+func (t *Translator) IncludeInInit() bool { return false }
+
+// Translate converts pgrep arguments to procs arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	// Scripts read bare PIDs from a pipe
+	if opts.Piped {
+		return translator.Result{Fallback: true}
+	}
+	return translateFlags(args, ps2procs.GetPSMode(opts.Mode))
+}
+
+// pgrep options that take a value, attached or in the next argument
+var valueLetters = map[ps2procs.PSMode]string{
+	ps2procs.ModeProcps: "dgGOPstuUrF",
+	ps2procs.ModeBSD:    "dgGPtuUFMN",
+}
+
+// Long options (procps) with their short equivalents
+var longOptions = map[string]rune{
+	"--delimiter":        'd',
+	"--list-name":        'l',
+	"--list-full":        'a',
+	"--count":            'c',
+	"--full":             'f',
+	"--pgroup":           'g',
+	"--group":            'G',
+	"--ignore-case":      'i',
+	"--newest":           'n',
+	"--oldest":           'o',
+	"--older":            'O',
+	"--parent":           'P',
+	"--session":          's',
+	"--terminal":         't',
+	"--euid":             'u',
+	"--uid":              'U',
+	"--inverse":          'v',
+	"--lightweight":      'w',
+	"--exact":            'x',
+	"--runstates":        'r',
+	"--pidfile":          'F',
+	"--logpidfile":       'L',
+	"--ignore-ancestors": 'A',
+}
+
+// Characters that make a pgrep pattern a regular expression
+const regexChars = `.*+?[](){}|^$\`
+
+func translateFlags(args []string, mode ps2procs.PSMode) translator.Result {
+	var sel ps2procs.Selection
+	var warnings []string
+	var sortArgs []string
+	threads := false
+	fallback := false
+
+	// option handles a single pgrep option, with its value if it takes one
+	option := func(c rune, val string) {
+		switch c {
+		case 'd', 'c', 'q', 'F', 'L':
+			// Delimiters, counts, quiet mode and pid files are for scripts
+			fallback = true
+		case 'l':
+			sel.Format = "pid,command"
+		case 'a':
+			if mode == ps2procs.ModeProcps {
+				sel.Format = "pid,command"
+			}
+			// BSD -a includes pgrep's ancestors, which procs always shows
+		case 'f':
+			// procs always matches the full command line
+		case 'i':
+			sel.IgnoreCase = true
+		case 'x':
+			sel.Partial = false
+		case 'v':
+			sel.Negate = true
+		case 'n':
+			sortArgs = []string{"--sortd", "start_time"}
+			warnings = append(warnings, "procs shows every match, newest first")
+		case 'o':
+			sortArgs = []string{"--sorta", "start_time"}
+			warnings = append(warnings, "procs shows every match, oldest first")
+		case 'w':
+			threads = true
+		case 'u', 'U':
+			sel.Users = append(sel.Users, val)
+		case 'G':
+			sel.Groups = append(sel.Groups, val)
+		case 'P':
+			sel.PPIDs = append(sel.PPIDs, val)
+		case 's':
+			sel.Sessions = append(sel.Sessions, val)
+		case 't':
+			sel.TTYs = append(sel.TTYs, val)
+		case 'A':
+			// procs never lists itself
+		default:
+			if val != "" {
+				warnings = append(warnings, fmt.Sprintf("ignoring -%c %s", c, val))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("ignoring -%c", c))
+			}
+		}
+	}
+
+	// pgrep matches patterns against part of the name unless -x is given
+	sel.Partial = true
+	var patterns []string
+	endOfOptions := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case endOfOptions || !strings.HasPrefix(arg, "-") || arg == "-":
+			patterns = append(patterns, arg)
+		case arg == "--":
+			endOfOptions = true
+		case strings.HasPrefix(arg, "--"):
+			name, val, hasVal := strings.Cut(arg, "=")
+			c, ok := longOptions[name]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("ignoring %s", arg))
+				continue
+			}
+			if !hasVal && strings.ContainsRune(valueLetters[mode], c) && i+1 < len(args) {
+				val = args[i+1]
+				i++
+			}
+			option(c, val)
+		default:
+			letters := arg[1:]
+			for j, c := range letters {
+				if !strings.ContainsRune(valueLetters[mode], c) {
+					option(c, "")
+					continue
+				}
+
+				// The value is the rest of the argument or the next one
+				val := letters[j+1:]
+				if val == "" && i+1 < len(args) {
+					val = args[i+1]
+					i++
+				}
+				option(c, val)
+				break
+			}
+		}
+	}
+
+	if fallback {
+		return translator.Result{Fallback: true}
+	}
+
+	for _, pattern := range patterns {
+		if strings.ContainsAny(pattern, regexChars) {
+			warnings = append(warnings, fmt.Sprintf("procs matches %q literally, not as a regular expression", pattern))
+		}
+	}
+	sel.Commands = patterns
+
+	// pgrep shows processes matching every criterion, and any item of a list
+	// procs combines all keywords one way, so several lists of several items
+	// can only be approximated
+	lists, long := 0, false
+	for _, list := range [][]string{sel.Commands, sel.Users, sel.Groups, sel.PPIDs, sel.Sessions, sel.TTYs} {
+		if len(list) > 0 {
+			lists++
+		}
+		long = long || len(list) > 1 || len(list) == 1 && strings.Contains(list[0], ",")
+	}
+	switch {
+	case lists == 0:
+		// pgrep needs something to match, so let it report the error
+		return translator.Result{Fallback: true}
+	case lists > 1 && long:
+		warnings = append(warnings, "procs can't combine lists with other criteria, showing processes matching any")
+	case lists > 1:
+		sel.MatchAll = true
+	}
+
+	procsArgs := []string{"--pager", "disable"}
+	procsArgs = append(procsArgs, sortArgs...)
+	if threads {
+		procsArgs = append(procsArgs, "--thread")
+	}
+	procsArgs = append(procsArgs, ps2procs.Select(sel, &warnings)...)
+	return translator.Result{Args: procsArgs, Warnings: warnings}
+}
@@ -0,0 +1,206 @@
+package pgrep2procs
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ps2procs"
+)
+
+// translate runs a translation with generated procs configs kept out of the
+// user's cache, and replaces their paths with "procs.toml"
+func translate(t *testing.T, args []string, mode ps2procs.PSMode) (translator.Result, string) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	result := translateFlags(args, mode)
+	var config string
+	for i := 1; i < len(result.Args); i++ {
+		if result.Args[i-1] == "--load-config" {
+			content, err := os.ReadFile(result.Args[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			config = string(content)
+			result.Args[i] = "procs.toml"
+		}
+	}
+	return result, config
+}
+
+func TestTranslateFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		mode     ps2procs.PSMode
+		expected []string
+		config   []string // expected config fragments
+		warns    bool
+		fallback bool
+	}{
+		{
+			name:     "pattern",
+			input:    []string{"nginx"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "nginx"},
+			config:   []string{`nonnumeric_search = "Partial"`, "kind = \"Command\"\nstyle = \"BrightWhite|Black\"\nnumeric_search = false\nnonnumeric_search = true"},
+		},
+		{
+			name:     "full command line with names",
+			input:    []string{"-fl", "nginx"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "nginx"},
+			config:   []string{"kind = \"Pid\"", "kind = \"Command\""},
+		},
+		{
+			name:     "user and pattern",
+			input:    []string{"-u", "root", "sshd"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--and", "root", "sshd"},
+		},
+		{
+			name:     "user list and pattern",
+			input:    []string{"-u", "root,daemon", "sshd"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root", "daemon", "sshd"},
+			warns:    true,
+		},
+		{
+			name:     "user list alone",
+			input:    []string{"-U", "root,daemon"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root", "daemon"},
+		},
+		{
+			name:     "group and parent",
+			input:    []string{"-G", "wheel", "-P1"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--and", "1", "wheel"},
+			config:   []string{"kind = \"Ppid\"\nstyle = \"BrightWhite|Black\"\nnumeric_search = true"},
+		},
+		{
+			name:     "exact matching is partial",
+			input:    []string{"-xi", "Nginx"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "Nginx"},
+			config:   []string{`nonnumeric_search = "Partial"`, `case = "Insensitive"`},
+			warns:    true,
+		},
+		{
+			name:     "inverse",
+			input:    []string{"-v", "bash"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--nor", "bash"},
+		},
+		{
+			name:     "newest",
+			input:    []string{"-n", "sshd"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "start_time", "--load-config", "procs.toml", "--or", "sshd"},
+			warns:    true,
+		},
+		{
+			name:     "oldest with long option",
+			input:    []string{"--oldest", "sshd"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--sorta", "start_time", "--load-config", "procs.toml", "--or", "sshd"},
+			warns:    true,
+		},
+		{
+			name:     "lightweight processes",
+			input:    []string{"-w", "java"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--thread", "--load-config", "procs.toml", "--or", "java"},
+		},
+		{
+			name:     "regex warns",
+			input:    []string{"^ssh.*"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "^ssh.*"},
+			warns:    true,
+		},
+		{
+			name:     "end of options",
+			input:    []string{"--", "-bash"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "-bash"},
+		},
+		{
+			name:     "count runs pgrep",
+			input:    []string{"-c", "nginx"},
+			mode:     ps2procs.ModeProcps,
+			fallback: true,
+		},
+		{
+			name:     "delimiter runs pgrep",
+			input:    []string{"-d,", "nginx"},
+			mode:     ps2procs.ModeProcps,
+			fallback: true,
+		},
+		{
+			name:     "nothing to match runs pgrep",
+			input:    []string{"-l"},
+			mode:     ps2procs.ModeProcps,
+			fallback: true,
+		},
+		{
+			name:     "BSD -a includes ancestors",
+			input:    []string{"-a", "zsh"},
+			mode:     ps2procs.ModeBSD,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "zsh"},
+		},
+		{
+			name:     "BSD quiet runs pgrep",
+			input:    []string{"-q", "zsh"},
+			mode:     ps2procs.ModeBSD,
+			fallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, config := translate(t, tt.input, tt.mode)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+			if result.Fallback != tt.fallback {
+				t.Errorf("translateFlags(%v) fallback = %v, want %v", tt.input, result.Fallback, tt.fallback)
+			}
+			for _, want := range tt.config {
+				if !strings.Contains(config, want) {
+					t.Errorf("translateFlags(%v) config missing %q:\n%s", tt.input, want, config)
+				}
+			}
+		})
+	}
+}
+
+func TestPipedFallback(t *testing.T) {
+	tr := &Translator{}
+	if result := tr.Translate([]string{"nginx"}, translator.Options{Piped: true}); !result.Fallback {
+		t.Errorf("Translate(nginx) piped fallback = false, want true")
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "pgrep2procs" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "pgrep2procs")
+	}
+	if tr.SourceTool() != "pgrep" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "pgrep")
+	}
+	if tr.TargetTool() != "procs" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "procs")
+	}
+	if tr.IncludeInInit() {
+		t.Error("IncludeInInit() = true, want false")
+	}
+}
@@ -0,0 +1,80 @@
+package pidof2procs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ps2procs"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the pidof to procs flag translation
+// It shows the named programs in a procs table instead of bare PIDs
+type Translator struct{}
+
+func (t *Translator) Name() string       { return "pidof2procs" }
+func (t *Translator) SourceTool() string { return "pidof" }
+func (t *Translator) TargetTool() string { return "procs" }
+
+// IncludeInInit is false since scripts in interactive shells rely on pidof's
+// exit status, which procs doesn't set
+func (t *Translator) IncludeInInit() bool { return false }
+
+// Translate converts pidof arguments to procs arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	// Scripts read bare PIDs from a pipe
+	if opts.Piped {
+		return translator.Result{Fallback: true}
+	}
+	return translateFlags(args)
+}
+
+func translateFlags(args []string) translator.Result {
+	var programs []string
+	var warnings []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || len(arg) < 2 {
+			programs = append(programs, arg)
+			continue
+		}
+
+	letters:
+		for j, c := range arg[1:] {
+			switch c {
+			case 'q', 'S':
+				// Quiet mode and separators are for scripts
+				return translator.Result{Fallback: true}
+			case 's':
+				warnings = append(warnings, "procs shows every match, not just one")
+			case 'o':
+				// Omitted PIDs, attached or in the next argument
+				val := arg[j+2:]
+				if val == "" && i+1 < len(args) {
+					val = args[i+1]
+					i++
+				}
+				warnings = append(warnings, fmt.Sprintf("procs can't omit processes, ignoring -o %s", val))
+				break letters
+			case 'c', 'n', 'x', 'z', 'w':
+				// procs looks at every process and its full command line
+			default:
+				warnings = append(warnings, fmt.Sprintf("ignoring -%c", c))
+			}
+		}
+	}
+
+	// pidof needs a program name, so let it report the error
+	if len(programs) == 0 {
+		return translator.Result{Fallback: true}
+	}
+
+	procsArgs := []string{"--pager", "disable"}
+	procsArgs = append(procsArgs, ps2procs.Select(ps2procs.Selection{Commands: programs, Partial: true}, &warnings)...)
+	return translator.Result{Args: procsArgs, Warnings: warnings}
+}
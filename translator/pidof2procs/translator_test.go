@@ -0,0 +1,99 @@
+package pidof2procs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+)
+
+func TestTranslateFlags(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+		warns    bool
+		fallback bool
+	}{
+		{
+			name:     "program",
+			input:    []string{"sshd"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "sshd"},
+		},
+		{
+			name:     "several programs",
+			input:    []string{"-x", "nginx", "php-fpm"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "nginx", "php-fpm"},
+		},
+		{
+			name:     "single shot",
+			input:    []string{"-s", "sshd"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "sshd"},
+			warns:    true,
+		},
+		{
+			name:     "omitted PIDs",
+			input:    []string{"-o", "%PPID", "bash"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "bash"},
+			warns:    true,
+		},
+		{
+			name:     "quiet runs pidof",
+			input:    []string{"-q", "sshd"},
+			fallback: true,
+		},
+		{
+			name:     "separator runs pidof",
+			input:    []string{"-S", ",", "sshd"},
+			fallback: true,
+		},
+		{
+			name:     "no program runs pidof",
+			input:    []string{"-s"},
+			fallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input)
+			for i := 1; i < len(result.Args); i++ {
+				if result.Args[i-1] == "--load-config" {
+					result.Args[i] = "procs.toml"
+				}
+			}
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+			if result.Fallback != tt.fallback {
+				t.Errorf("translateFlags(%v) fallback = %v, want %v", tt.input, result.Fallback, tt.fallback)
+			}
+		})
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "pidof2procs" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "pidof2procs")
+	}
+	if tr.SourceTool() != "pidof" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "pidof")
+	}
+	if tr.TargetTool() != "procs" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "procs")
+	}
+	if tr.IncludeInInit() {
+		t.Error("IncludeInInit() = true, want false")
+	}
+	if result := tr.Translate([]string{"sshd"}, translator.Options{Piped: true}); !result.Fallback {
+		t.Error("Translate(sshd) piped fallback = false, want true")
+	}
+}
@@ -1,6 +1,12 @@
 package ps2procs
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/kluzzebass/reflag/translator"
@@ -19,8 +25,34 @@ func (t *Translator) TargetTool() string  { return "procs" }
 func (t *Translator) IncludeInInit() bool { return true }
 
 // Translate converts ps arguments to procs arguments
-func (t *Translator) Translate(args []string, mode string) []string {
-	return translateFlags(args)
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translateFlags(args, GetPSMode(opts.Mode))
+}
+
+// PSMode determines which ps flavor to emulate
+type PSMode int
+
+const (
+	ModeBSD    PSMode = iota // macOS and BSD ps
+	ModeProcps               // procps-ng ps (Linux)
+)
+
+// GetPSMode returns the ps compatibility mode based on mode string or OS detection
+func GetPSMode(mode string) PSMode {
+	switch strings.ToLower(mode) {
+	case "bsd":
+		return ModeBSD
+	case "gnu", "procps":
+		return ModeProcps
+	}
+
+	// Auto-detect based on OS
+	switch runtime.GOOS {
+	case "darwin", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return ModeBSD
+	default:
+		return ModeProcps
+	}
 }
 
 // Flags to ignore (procs shows all processes by default with good format)
@@ -80,13 +112,380 @@ var columnMap = map[string]string{
 	"start":    "start_time",
 	"stime":    "start_time",
 	"lstart":   "start_time",
+	"pgid":     "pgid",
+	"pgrp":     "pgid",
+	"nlwp":     "threads",
+	"thcount":  "threads",
+	"sid":      "session",
+	"sess":     "session",
+	"session":  "session",
+}
+
+// procs config column kinds for the procs column names in columnMap
+var configKinds = map[string]string{
+	"pid":        "Pid",
+	"ppid":       "Ppid",
+	"uid":        "Uid",
+	"user":       "User",
+	"gid":        "Gid",
+	"group":      "Group",
+	"command":    "Command",
+	"cpu":        "UsageCpu",
+	"mem":        "UsageMem",
+	"rss":        "VmRss",
+	"vsz":        "VmSize",
+	"state":      "State",
+	"tty":        "Tty",
+	"time":       "CpuTime",
+	"elapsed":    "ElapsedTime",
+	"nice":       "Nice",
+	"priority":   "Priority",
+	"start_time": "StartTime",
+	"session":    "Session",
+	"pgid":       "Pgid",
+	"threads":    "Threads",
+}
+
+// column is an output column selected with -o, -O or --format
+type column struct {
+	kind   string // procs column name
+	header string // custom header from ps's name=HEADER, "" for the default
+}
+
+// parseFormat parses a ps format list such as "pid,user,%cpu" or "pid=PROCESS"
+// Columns are separated by commas or blanks; a header after = extends to the
+// end of the list, like in ps
+// Returns the columns and the names of ps columns procs doesn't have
+func parseFormat(format string) (columns []column, unknown []string) {
+	for format != "" {
+		format = strings.TrimLeft(format, ", ")
+		if format == "" {
+			break
+		}
+		end := strings.IndexAny(format, ", =")
+		if end == -1 {
+			end = len(format)
+		}
+		name := format[:end]
+		header := ""
+		if end < len(format) && format[end] == '=' {
+			header = format[end+1:]
+			end = len(format)
+		}
+		format = format[end:]
+
+		if kind, ok := columnMap[strings.ToLower(name)]; ok {
+			columns = append(columns, column{kind: kind, header: header})
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	return columns, unknown
+}
+
+// Job format columns for -j and BSD j, per mode
+// procs has no tpgid or jobc columns, so they're left out
+var jobFormats = map[PSMode][2]string{
+	ModeProcps: {"pid,pgid,sid,tty,time,cmd", "ppid,pid,pgid,sid,tty,stat,uid,time,command"},
+	ModeBSD:    {"user,pid,ppid,pgid,sess,stat,tty,time,command", "user,pid,ppid,pgid,sess,stat,tty,time,command"},
+}
+
+// procs' default columns, used when a selection needs a generated config
+const defaultFormat = "pid,user,tty,%cpu,%mem,time,command"
+
+// Long options selecting processes, with their short option equivalents
+var longSelectors = map[string]rune{
+	"--pid":       'p',
+	"--ppid":      'P',
+	"--quick-pid": 'q',
+	"--user":      'u',
+	"--User":      'U',
+	"--group":     'G',
+	"--Group":     'G',
+	"--tty":       't',
+	"--sid":       's',
+}
+
+// selection holds ps's process selection options
+// ps shows the union of the selected processes, or all others with -N
+type selection struct {
+	pids       []string
+	ppids      []string
+	users      []string
+	commands   []string
+	groups     []string
+	ttys       []string
+	sessions   []string
+	negate     bool
+	all        bool // match every keyword rather than any
+	partial    bool // match names by substring rather than exactly
+	ignoreCase bool
+}
+
+// add adds the items of a ps selection list such as "1,2 3" for an option
+func (s *selection) add(opt rune, list string) {
+	for _, item := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' }) {
+		switch opt {
+		case 'p', 'q':
+			s.pids = append(s.pids, item)
+		case 'P':
+			s.ppids = append(s.ppids, item)
+		case 'u', 'U':
+			s.users = append(s.users, item)
+		case 'C':
+			s.commands = append(s.commands, item)
+		case 'G':
+			s.groups = append(s.groups, item)
+		case 'g': // procps: a session or an effective group name
+			if isNumber(item) {
+				s.sessions = append(s.sessions, item)
+			} else {
+				s.groups = append(s.groups, item)
+			}
+		case 't': // procs shows ttys as tty1 or pts/0
+			item = strings.TrimPrefix(item, "/dev/")
+			if isNumber(item) {
+				item = "tty" + item
+			}
+			s.ttys = append(s.ttys, item)
+		case 's':
+			s.sessions = append(s.sessions, item)
+		}
+	}
+}
+
+// logic returns the procs option combining the keywords
+func (s *selection) logic() string {
+	switch {
+	case s.negate && s.all:
+		return "--nand"
+	case s.negate:
+		return "--nor"
+	case s.all:
+		return "--and"
+	}
+	return "--or"
+}
+
+func (s *selection) empty() bool {
+	return len(s.keywords()) == 0
+}
+
+// keywords returns the procs search keywords for the selection
+func (s *selection) keywords() []string {
+	var keywords []string
+	for _, list := range [][]string{s.pids, s.ppids, s.users, s.commands, s.groups, s.ttys, s.sessions} {
+		keywords = append(keywords, list...)
+	}
+	return keywords
+}
+
+// kinds returns the procs columns the selection searches
+func (s *selection) kinds() []string {
+	var kinds []string
+	for _, kind := range []string{"pid", "ppid", "uid", "user", "command", "gid", "group", "tty", "session"} {
+		if numeric, nonnumeric := s.searches(kind); numeric || nonnumeric {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// searches reports whether numeric and non-numeric keywords should match a
+// column, so only the columns the selection refers to are searched
+// Numeric users and groups are IDs, like in ps
+func (s *selection) searches(kind string) (numeric, nonnumeric bool) {
+	switch kind {
+	case "pid":
+		return len(s.pids) > 0, false
+	case "ppid":
+		return len(s.ppids) > 0, false
+	case "uid":
+		return slices.ContainsFunc(s.users, isNumber), false
+	case "user":
+		return false, !all(s.users, isNumber)
+	case "command":
+		return false, len(s.commands) > 0
+	case "gid":
+		return slices.ContainsFunc(s.groups, isNumber), false
+	case "group":
+		return false, !all(s.groups, isNumber)
+	case "tty":
+		return false, len(s.ttys) > 0
+	case "session":
+		return len(s.sessions) > 0, false
+	}
+	return false, false
 }
 
-func translateFlags(args []string) []string {
+func isNumber(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// all reports whether every item satisfies f; it's true for an empty list
+func all(items []string, f func(string) bool) bool {
+	return !slices.ContainsFunc(items, func(item string) bool { return !f(item) })
+}
+
+// Column styles and alignment for the generated procs config
+var configStyles = map[string]string{
+	"cpu":   "ByPercentage",
+	"mem":   "ByPercentage",
+	"rss":   "ByUnit",
+	"vsz":   "ByUnit",
+	"state": "ByState",
+}
+
+// columnsConfig returns a procs config that shows exactly the given columns
+// With a selection, its columns are matched exactly (or partially) and the
+// others aren't searched
+func columnsConfig(columns []column, sel *selection) string {
+	var b strings.Builder
+	if sel != nil {
+		nonnumeric := "Exact"
+		if sel.partial {
+			nonnumeric = "Partial"
+		}
+		b.WriteString("[search]\n")
+		b.WriteString("numeric_search = \"Exact\"\n")
+		fmt.Fprintf(&b, "nonnumeric_search = %q\n", nonnumeric)
+		if sel.ignoreCase {
+			b.WriteString("case = \"Insensitive\"\n")
+		}
+	}
+	for i, col := range columns {
+		if i > 0 || sel != nil {
+			b.WriteString("\n")
+		}
+		style, ok := configStyles[col.kind]
+		if !ok {
+			style = "BrightWhite|Black"
+		}
+		align := "Right"
+		if col.kind == "user" || col.kind == "group" || col.kind == "command" || col.kind == "tty" || col.kind == "state" {
+			align = "Left"
+		}
+		numeric := col.kind == "pid" || col.kind == "ppid" || col.kind == "uid" || col.kind == "gid"
+		nonnumeric := col.kind == "user" || col.kind == "group" || col.kind == "command"
+		if sel != nil {
+			numeric, nonnumeric = sel.searches(col.kind)
+		}
+
+		b.WriteString("[[columns]]\n")
+		fmt.Fprintf(&b, "kind = %q\n", configKinds[col.kind])
+		fmt.Fprintf(&b, "style = %q\n", style)
+		fmt.Fprintf(&b, "numeric_search = %t\n", numeric)
+		fmt.Fprintf(&b, "nonnumeric_search = %t\n", nonnumeric)
+		fmt.Fprintf(&b, "align = %q\n", align)
+		if col.header != "" {
+			fmt.Fprintf(&b, "header = %q\n", col.header)
+		}
+	}
+	return b.String()
+}
+
+// writeConfig stores a generated procs config and returns its path
+// It's a variable so tests can avoid touching the file system
+var writeConfig = writeCachedConfig
+
+// writeCachedConfig writes a procs config to the user's cache directory
+// Files are named after their content, so repeated invocations reuse them
+func writeCachedConfig(content string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "reflag")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(content))
+	path := filepath.Join(dir, fmt.Sprintf("procs-%x.toml", sum[:8]))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// translateColumns converts -o/--format and -O column lists to procs options
+// A single column uses --only; anything else needs a generated config, as
+// does exact matching for a process selection
+func translateColumns(format, insert []string, sel *selection, warnings *[]string) []string {
+	var columns, inserted []column
+	for _, f := range format {
+		cols, unknown := parseFormat(f)
+		columns = append(columns, cols...)
+		for _, name := range unknown {
+			*warnings = append(*warnings, fmt.Sprintf("procs has no %s column", name))
+		}
+	}
+	for _, f := range insert {
+		cols, unknown := parseFormat(f)
+		inserted = append(inserted, cols...)
+		for _, name := range unknown {
+			*warnings = append(*warnings, fmt.Sprintf("procs has no %s column", name))
+		}
+	}
+
+	var procsArgs []string
+	if !sel.empty() {
+		// procs' Command column holds the whole command line, which an exact
+		// name never matches
+		if len(sel.commands) > 0 && !sel.partial {
+			*warnings = append(*warnings, "procs matches command names anywhere in the command line")
+			sel.partial = true
+		}
+		// procs only searches the columns it shows
+		if len(columns) == 0 {
+			columns, _ = parseFormat(defaultFormat)
+		}
+		for _, kind := range sel.kinds() {
+			if !slices.ContainsFunc(columns, func(col column) bool { return col.kind == kind }) {
+				columns = append(columns, column{kind: kind})
+			}
+		}
+		path, err := writeConfig(columnsConfig(columns, sel))
+		if err != nil {
+			*warnings = append(*warnings, "can't write procs selection config, matching loosely: "+err.Error())
+		} else {
+			procsArgs = append(procsArgs, "--load-config", path)
+		}
+	} else if len(columns) == 1 && columns[0].header == "" {
+		procsArgs = append(procsArgs, "--only", columns[0].kind)
+	} else if len(columns) > 0 {
+		path, err := writeConfig(columnsConfig(columns, nil))
+		if err != nil {
+			*warnings = append(*warnings, "can't write procs column config: "+err.Error())
+			procsArgs = append(procsArgs, "--only", columns[0].kind)
+		} else {
+			procsArgs = append(procsArgs, "--load-config", path)
+		}
+	}
+
+	// -O adds columns to procs' default view
+	for _, col := range inserted {
+		if col.header != "" {
+			*warnings = append(*warnings, "procs can't rename inserted columns")
+		}
+		procsArgs = append(procsArgs, "--insert", col.kind)
+	}
+	return procsArgs
+}
+
+func translateFlags(args []string, mode PSMode) translator.Result {
 	var procsArgs []string
 	var searchTerms []string
+	var sel selection
+	var warnings []string
+	var format, insert []string
+	var jobFormat string
+	threads := false
 	skipNext := false
 	hasPagerFlag := false
+	firstWord := true
 
 	for i, arg := range args {
 		if skipNext {
@@ -102,14 +501,24 @@ func translateFlags(args []string) []string {
 
 				switch opt {
 				case "--sort":
-					procsArgs = append(procsArgs, translateSort(val)...)
-				case "--user", "--User":
-					searchTerms = append(searchTerms, val)
-				case "--pid":
-					searchTerms = append(searchTerms, val)
+					procsArgs = append(procsArgs, translateSort(val, &warnings)...)
 				case "--pager":
 					hasPagerFlag = true
 					procsArgs = append(procsArgs, arg)
+				case "--format":
+					format = append(format, val)
+				default:
+					if c, ok := longSelectors[opt]; ok {
+						sel.add(c, val)
+					}
+				}
+				continue
+			}
+
+			if c, ok := longSelectors[arg]; ok {
+				if i+1 < len(args) {
+					sel.add(c, args[i+1])
+					skipNext = true
 				}
 				continue
 			}
@@ -117,8 +526,20 @@ func translateFlags(args []string) []string {
 			switch arg {
 			case "--forest":
 				procsArgs = append(procsArgs, "--tree")
+			case "--deselect":
+				sel.negate = true
 			case "--headers", "--no-headers":
 				// Ignore
+			case "--format":
+				if i+1 < len(args) {
+					format = append(format, args[i+1])
+					skipNext = true
+				}
+			case "--sort":
+				if i+1 < len(args) {
+					procsArgs = append(procsArgs, translateSort(args[i+1], &warnings)...)
+					skipNext = true
+				}
 			case "--pager":
 				hasPagerFlag = true
 				procsArgs = append(procsArgs, arg)
@@ -134,6 +555,12 @@ func translateFlags(args []string) []string {
 			continue
 		}
 
+		// procps reads "-aux" as BSD "aux", since there's no user named "x"
+		dashed := mode == ModeProcps && isDashedBSDOptions(arg, mode)
+		if dashed {
+			arg = arg[1:]
+		}
+
 		// Handle UNIX-style options (with dash)
 		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
 			flags := arg[1:]
@@ -141,63 +568,66 @@ func translateFlags(args []string) []string {
 			// Check for flags that take values
 			for j, c := range flags {
 				switch c {
-				case 'u', 'U': // user
-					remaining := flags[j+1:]
-					var val string
-					if len(remaining) > 0 {
-						val = string(remaining)
-					} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-						val = args[i+1]
-						skipNext = true
+				case 'u', 'U', 'p', 'q', 'C', 'G', 'g', 't', 's': // process selection
+					if mode == ModeBSD && (c == 'u' || c == 'g' || c == 's' || c == 'q' || c == 'C') {
+						// BSD -u is the user-oriented format, procs' default,
+						// -C changes how CPU usage is computed, and -g, -q
+						// and -s take no value
+						continue
 					}
-					if val != "" {
-						searchTerms = append(searchTerms, val)
-					}
-					goto nextArg
-				case 'p': // pid
-					remaining := flags[j+1:]
-					var val string
-					if len(remaining) > 0 {
-						val = string(remaining)
-					} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+					val := flags[j+1:]
+					if val == "" && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
 						val = args[i+1]
 						skipNext = true
 					}
-					if val != "" {
-						searchTerms = append(searchTerms, val)
-					}
+					sel.add(c, val)
 					goto nextArg
-				case 'C': // command name
+				case 'o', 'O': // output format
 					remaining := flags[j+1:]
 					var val string
 					if len(remaining) > 0 {
 						val = string(remaining)
-					} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+					} else if i+1 < len(args) {
 						val = args[i+1]
 						skipNext = true
 					}
-					if val != "" {
-						searchTerms = append(searchTerms, val)
+					if c == 'o' {
+						format = append(format, val)
+					} else {
+						insert = append(insert, val)
 					}
 					goto nextArg
-				case 'o', 'O': // output format - skip value
-					if j+1 >= len(flags) && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-						skipNext = true
+				case 'H': // tree view
+					procsArgs = append(procsArgs, "--tree")
+				case 'd', 'm', 'r':
+					if mode != ModeBSD {
+						// All except session leaders, running only
+						if c == 'm' { // threads after processes
+							threads = true
+						}
+						continue
 					}
-					goto nextArg
-				case 'G', 'g': // group - skip value
-					if j+1 >= len(flags) && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-						skipNext = true
+					switch c {
+					case 'd': // descendancy order (FreeBSD)
+						procsArgs = append(procsArgs, "--tree")
+					case 'm': // sort by memory
+						procsArgs = append(procsArgs, "--sortd", "mem")
+					case 'r': // sort by CPU
+						procsArgs = append(procsArgs, "--sortd", "cpu")
 					}
-					goto nextArg
-				case 't': // tty - skip value
-					if j+1 >= len(flags) && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-						skipNext = true
+				case 'N': // negate the selection
+					sel.negate = true
+				case 'L', 'T': // threads (procps), this terminal and keywords (BSD)
+					if mode == ModeProcps {
+						threads = true
 					}
-					goto nextArg
-				case 'H': // tree view
-					procsArgs = append(procsArgs, "--tree")
-				case 'e', 'A', 'a', 'x', 'f', 'l', 'j', 'v', 'w', 'r', 'd', 'N', 'T', 's', 'c', 'm', 'L':
+				case 'M': // threads (BSD)
+					if mode == ModeBSD {
+						threads = true
+					}
+				case 'j': // job format
+					jobFormat = jobFormats[mode][0]
+				case 'e', 'A', 'a', 'x', 'f', 'l', 'v', 'w', 'c':
 					// Ignored flags
 				default:
 					// Unknown flag, pass through
@@ -208,26 +638,99 @@ func translateFlags(args []string) []string {
 			continue
 		}
 
-		// Handle BSD-style options (no dash) - like "aux", "ef"
-		if len(arg) > 0 && !strings.HasPrefix(arg, "-") {
-			// Check if it looks like BSD ps options (common patterns)
-			if isBSDStyleOptions(arg) {
-				for _, c := range arg {
-					switch c {
-					case 'f': // forest/tree (BSD)
+		// Handle BSD-style options (no dash) - like "aux", "axo pid"
+		// ps only accepts them in the first argument without a dash, but a
+		// later k, o or O still takes a value, as in "aux k-%mem"
+		// A word ending with a value option needs a next argument, so a last
+		// word like "cat" is a search term
+		lastWithoutValue := i == len(args)-1 && needsNextValue(arg, mode)
+		if (firstWord || dashed || isBSDValueOption(arg)) && !lastWithoutValue && isBSDStyleOptions(arg, mode) {
+			firstWord = false
+			for j, c := range arg {
+				if !strings.ContainsRune(bsdValueLetters[mode], c) {
+					switch {
+					case c == 'f' && mode == ModeProcps: // ASCII-art forest
 						procsArgs = append(procsArgs, "--tree")
-						// Most BSD flags can be ignored as procs shows all with good defaults
-						// a, u, x, e, etc. are about process selection which procs handles
+					case c == 'd' && mode == ModeBSD: // descendancy order (FreeBSD)
+						procsArgs = append(procsArgs, "--tree")
+					case c == 'm' && mode == ModeBSD: // sort by memory
+						procsArgs = append(procsArgs, "--sortd", "mem")
+					case c == 'r' && mode == ModeBSD: // sort by CPU
+						procsArgs = append(procsArgs, "--sortd", "cpu")
+					case c == 'H' || c == 'M' || c == 'm' && mode == ModeProcps: // threads
+						threads = true
+					case c == 'j': // job format
+						jobFormat = jobFormats[mode][1]
 					}
+					// Most BSD flags can be ignored as procs shows all with good defaults
+					// a, u, x, e, etc. are about process selection which procs handles
+					continue
 				}
-				continue
+
+				// The value is the rest of the word or the next argument
+				val := arg[j+1:]
+				if val == "" && i+1 < len(args) {
+					val = args[i+1]
+					skipNext = true
+				}
+				if val == "" {
+					break
+				}
+				switch c {
+				case 'o':
+					format = append(format, val)
+				case 'O':
+					if mode == ModeProcps && isSortSpec(val) {
+						// procps O with +/- keys orders the listing like k
+						procsArgs = append(procsArgs, translateSort(val, &warnings)...)
+					} else {
+						insert = append(insert, val)
+					}
+				case 'k':
+					procsArgs = append(procsArgs, translateSort(val, &warnings)...)
+				case 'p', 'q', 'U', 'G', 't':
+					sel.add(c, val)
+				}
+				break
 			}
+			continue
+		}
+		firstWord = false
 
-			// Otherwise treat as a search term (could be PID or pattern)
+		// Otherwise it's a PID list, like in ps, or a search term
+		if strings.Trim(arg, "0123456789, ") == "" {
+			sel.add('p', arg)
+		} else {
 			searchTerms = append(searchTerms, arg)
 		}
 	}
 
+	if sel.negate && sel.empty() && len(searchTerms) == 0 {
+		// procs can only exclude processes matching keywords, not ps's
+		// default selection, so leave it to ps
+		return translator.Result{Fallback: true}
+	}
+
+	// The job format applies unless columns are chosen explicitly
+	if len(format) == 0 && jobFormat != "" {
+		format = append(format, jobFormat)
+	}
+	procsArgs = append(procsArgs, translateColumns(format, insert, &sel, &warnings)...)
+
+	if threads {
+		if mode == ModeBSD {
+			warnings = append(warnings, "procs can't show per-thread rows on this system")
+		} else {
+			procsArgs = append(procsArgs, "--thread")
+		}
+	}
+
+	// ps selects the union of every selector, or everything else with -N
+	if !sel.empty() || (sel.negate && len(searchTerms) > 0) {
+		procsArgs = append(procsArgs, sel.logic())
+		searchTerms = append(sel.keywords(), searchTerms...)
+	}
+
 	// Add default --pager disable if user hasn't specified it
 	if !hasPagerFlag {
 		procsArgs = append([]string{"--pager", "disable"}, procsArgs...)
@@ -237,56 +740,190 @@ func translateFlags(args []string) []string {
 	result := make([]string, 0, len(procsArgs)+len(searchTerms))
 	result = append(result, procsArgs...)
 	result = append(result, searchTerms...)
-	return result
+	return translator.Result{Args: result, Warnings: warnings}
+}
+
+// Option letters ps accepts without a dash
+var bsdLetters = map[PSMode]string{
+	ModeProcps: "acefghjklmnopqrstuvwxHLOSTUXZ",
+	ModeBSD:    "acdefhjlmopqrtuvwxACEGLMOSTUXZ",
+}
+
+// BSD option letters that take a value, attached or in the next argument
+var bsdValueLetters = map[PSMode]string{
+	ModeProcps: "kopqtOU",
+	ModeBSD:    "optGOU",
 }
 
-// isBSDStyleOptions checks if a string looks like BSD ps options
-func isBSDStyleOptions(s string) bool {
-	// BSD ps options are typically short combinations like "aux", "ef", "axjf"
-	if len(s) > 5 || len(s) < 1 {
+// isBSDStyleOptions reports whether a word is a group of BSD ps options
+// rather than a search term, using ps's own rules: every letter must be a
+// valid option, and a value attached to an option letter must be valid for it
+// (so "aux" and "axopid" are options, while "root" and "sed" are search terms)
+func isBSDStyleOptions(s string, mode PSMode) bool {
+	if s == "" {
 		return false
 	}
-	// Must contain at least one of the common BSD option chars
-	hasCommonBSD := false
-	commonBSD := "auxef"
-	for _, c := range s {
-		if strings.ContainsRune(commonBSD, c) {
-			hasCommonBSD = true
-			break
+	for j, c := range s {
+		if !strings.ContainsRune(bsdLetters[mode], c) {
+			return false
+		}
+		if strings.ContainsRune(bsdValueLetters[mode], c) {
+			val := s[j+1:]
+			return val == "" || validBSDValue(c, val, mode)
 		}
 	}
-	if !hasCommonBSD {
+	return true
+}
+
+// isDashedBSDOptions reports whether a dash option such as "-aux" is meant as
+// BSD options: -u followed by more option letters rather than a user name
+func isDashedBSDOptions(s string, mode PSMode) bool {
+	flags, ok := strings.CutPrefix(s, "-")
+	if !ok {
 		return false
 	}
-	// All chars must be valid BSD option chars
-	validBSDChars := "adefghjlmnoprstuvwxAJLMNOPRSTUVWX"
-	for _, c := range s {
-		if !strings.ContainsRune(validBSDChars, c) {
+	u := strings.IndexRune(flags, 'u')
+	return u != -1 && u < len(flags)-1 && isBSDStyleOptions(flags, mode)
+}
+
+// isBSDValueOption reports whether a word is a BSD option that takes a value
+// in the word itself or the next argument, such as "k-%mem" or "o"
+func isBSDValueOption(s string) bool {
+	return s != "" && strings.ContainsRune("kOo", rune(s[0]))
+}
+
+// needsNextValue reports whether a BSD options word ends with an option that
+// reads its value from the next argument, such as the t in "cat"
+func needsNextValue(s string, mode PSMode) bool {
+	i := strings.IndexAny(s, bsdValueLetters[mode])
+	return i != -1 && i == len(s)-1
+}
+
+// validBSDValue reports whether an attached value is plausible for a BSD option
+func validBSDValue(c rune, val string, mode PSMode) bool {
+	switch c {
+	case 'p', 'q': // PID list
+		return strings.Trim(val, "0123456789,") == ""
+	case 'o', 'O': // format list (or sort keys for procps O)
+		columns, unknown := parseFormat(val)
+		if len(columns) > 0 && len(unknown) == 0 {
+			return true
+		}
+		return c == 'O' && mode == ModeProcps && validSortSpec(val)
+	case 'k': // sort keys
+		return validSortSpec(val)
+	case 't': // tty list such as pts/1 or tty2
+		return strings.ContainsAny(val, "0123456789/?")
+	}
+	// User and group names must be a separate argument
+	return false
+}
+
+// validSortSpec reports whether every key in a sort spec is a known column
+func validSortSpec(spec string) bool {
+	for _, key := range strings.Split(spec, ",") {
+		key = strings.TrimLeft(key, "+-")
+		if _, ok := columnMap[strings.ToLower(key)]; !ok {
 			return false
 		}
 	}
 	return true
 }
 
-// translateSort converts ps sort column to procs sort
-func translateSort(col string) []string {
-	// Remove leading +/- for direction
-	desc := false
-	if strings.HasPrefix(col, "-") {
-		desc = true
-		col = col[1:]
-	} else if strings.HasPrefix(col, "+") {
-		col = col[1:]
+// translateSort converts a ps sort spec such as "-%cpu,pid" to a procs sort.
+// procs sorts by a single column, so only the primary key is used and any
+// further keys are dropped with a warning
+func translateSort(spec string, warnings *[]string) []string {
+	var procsArgs []string
+	var ignored []string
+	for _, key := range strings.Split(spec, ",") {
+		// Remove leading +/- for direction
+		col := strings.TrimLeft(key, "+-")
+		if col == "" {
+			continue
+		}
+		desc := strings.HasPrefix(key, "-")
+
+		procsCol, ok := columnMap[strings.ToLower(col)]
+		if !ok {
+			*warnings = append(*warnings, fmt.Sprintf("procs can't sort by %s", col))
+			continue
+		}
+		if procsArgs != nil {
+			ignored = append(ignored, key)
+			continue
+		}
+
+		if desc {
+			procsArgs = []string{"--sortd", procsCol}
+		} else {
+			procsArgs = []string{"--sorta", procsCol}
+		}
+	}
+
+	if len(ignored) > 0 {
+		*warnings = append(*warnings, fmt.Sprintf("procs sorts by one column, ignoring %s", strings.Join(ignored, ",")))
+	}
+	return procsArgs
+}
+
+// Column returns the procs column for a ps column name such as "%cpu"
+func Column(name string) (string, bool) {
+	kind, ok := columnMap[strings.ToLower(name)]
+	return kind, ok
+}
+
+// Selection describes the processes to show, for translators of other
+// process tools that select them like ps
+type Selection struct {
+	PIDs       []string
+	PPIDs      []string
+	Users      []string // names or UIDs
+	Groups     []string // names or GIDs
+	Sessions   []string
+	TTYs       []string
+	Commands   []string
+	Partial    bool   // match by substring; always the case with Commands
+	IgnoreCase bool   // match commands regardless of case
+	MatchAll   bool   // show processes matching every keyword rather than any
+	Negate     bool   // show the processes that don't match
+	Format     string // ps format list of the columns to show, "" for procs' defaults
+}
+
+// Select returns procs arguments that show the selected processes, matching
+// each keyword against its own column the way ps2procs does for -p and -u
+func Select(s Selection, warnings *[]string) []string {
+	sel := selection{negate: s.Negate, all: s.MatchAll, partial: s.Partial, ignoreCase: s.IgnoreCase}
+	for _, list := range []struct {
+		opt   rune
+		items []string
+	}{{'p', s.PIDs}, {'P', s.PPIDs}, {'u', s.Users}, {'G', s.Groups}, {'s', s.Sessions}, {'t', s.TTYs}} {
+		for _, item := range list.items {
+			sel.add(list.opt, item)
+		}
+	}
+	// Commands are patterns, which may contain blanks and commas
+	sel.commands = append(sel.commands, s.Commands...)
+	if sel.empty() {
+		return nil
 	}
 
-	// Map column name
-	procsCol := col
-	if mapped, ok := columnMap[strings.ToLower(col)]; ok {
-		procsCol = mapped
+	var format []string
+	if s.Format != "" {
+		format = append(format, s.Format)
 	}
+	procsArgs := translateColumns(format, nil, &sel, warnings)
+	procsArgs = append(procsArgs, sel.logic())
+	return append(procsArgs, sel.keywords()...)
+}
 
-	if desc {
-		return []string{"--sortd", procsCol}
+// isSortSpec reports whether a procps BSD O value is a sort spec rather than
+// a format list: ps treats it as one when a key has a +/- direction
+func isSortSpec(val string) bool {
+	for _, key := range strings.Split(val, ",") {
+		if strings.HasPrefix(key, "+") || strings.HasPrefix(key, "-") {
+			return validSortSpec(val)
+		}
 	}
-	return []string{"--sorta", procsCol}
+	return false
 }
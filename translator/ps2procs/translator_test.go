@@ -1,11 +1,29 @@
 package ps2procs
 
 import (
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
+// stubConfig replaces the procs config writer for a test and returns a
+// pointer to the last config written
+func stubConfig(t *testing.T) *string {
+	t.Helper()
+	var written string
+	orig := writeConfig
+	writeConfig = func(content string) (string, error) {
+		written = content
+		return "procs.toml", nil
+	}
+	t.Cleanup(func() { writeConfig = orig })
+	return &written
+}
+
 func TestTranslateFlags(t *testing.T) {
+	stubConfig(t)
+
 	tests := []struct {
 		name     string
 		input    []string
@@ -52,43 +70,43 @@ func TestTranslateFlags(t *testing.T) {
 		{
 			name:     "forest BSD f",
 			input:    []string{"axjf"},
-			expected: []string{"--pager", "disable", "--tree"},
+			expected: []string{"--pager", "disable", "--tree", "--load-config", "procs.toml"},
 		},
 
 		// User filter
 		{
 			name:     "user filter -u",
 			input:    []string{"-u", "root"},
-			expected: []string{"--pager", "disable", "root"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root"},
 		},
 		{
 			name:     "user filter -U",
 			input:    []string{"-U", "www-data"},
-			expected: []string{"--pager", "disable", "www-data"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "www-data"},
 		},
 		{
 			name:     "user filter attached",
 			input:    []string{"-uroot"},
-			expected: []string{"--pager", "disable", "root"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root"},
 		},
 
 		// PID filter
 		{
 			name:     "pid filter",
 			input:    []string{"-p", "1234"},
-			expected: []string{"--pager", "disable", "1234"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1234"},
 		},
 		{
 			name:     "pid filter attached",
 			input:    []string{"-p1234"},
-			expected: []string{"--pager", "disable", "1234"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1234"},
 		},
 
 		// Command filter
 		{
 			name:     "command filter",
 			input:    []string{"-C", "nginx"},
-			expected: []string{"--pager", "disable", "nginx"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "nginx"},
 		},
 
 		// Sort
@@ -122,12 +140,12 @@ func TestTranslateFlags(t *testing.T) {
 		{
 			name:     "ps with user",
 			input:    []string{"-ef", "-u", "root"},
-			expected: []string{"--pager", "disable", "root"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root"},
 		},
 		{
 			name:     "ps tree with user",
 			input:    []string{"--forest", "-u", "root"},
-			expected: []string{"--pager", "disable", "--tree", "root"},
+			expected: []string{"--pager", "disable", "--tree", "--load-config", "procs.toml", "--or", "root"},
 		},
 
 		// Search term (not BSD options)
@@ -139,19 +157,19 @@ func TestTranslateFlags(t *testing.T) {
 		{
 			name:     "search by pid",
 			input:    []string{"1234"},
-			expected: []string{"--pager", "disable", "1234"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1234"},
 		},
 
 		// Ignored flags with values
 		{
-			name:     "output format ignored",
+			name:     "output format",
 			input:    []string{"-o", "pid,comm"},
-			expected: []string{"--pager", "disable"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
 		},
 		{
-			name:     "tty ignored",
+			name:     "tty filter",
 			input:    []string{"-t", "pts/0"},
-			expected: []string{"--pager", "disable"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "pts/0"},
 		},
 
 		// Pager flag handling
@@ -168,13 +186,13 @@ func TestTranslateFlags(t *testing.T) {
 		{
 			name:     "user override pager with other flags",
 			input:    []string{"--tree", "--pager=auto", "-u", "root"},
-			expected: []string{"--tree", "--pager=auto", "root"},
+			expected: []string{"--tree", "--pager=auto", "--load-config", "procs.toml", "--or", "root"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := translateFlags(tt.input)
+			result := translateFlags(tt.input, ModeProcps).Args
 			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result, tt.expected)
 			}
@@ -182,28 +200,751 @@ func TestTranslateFlags(t *testing.T) {
 	}
 }
 
+func TestColumns(t *testing.T) {
+	config := stubConfig(t)
+
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+		config   []string // kinds and headers expected in the generated config
+		warns    bool
+	}{
+		{
+			name:     "single column",
+			input:    []string{"-o", "pid"},
+			expected: []string{"--pager", "disable", "--only", "pid"},
+		},
+		{
+			name:     "attached value",
+			input:    []string{"-o%cpu"},
+			expected: []string{"--pager", "disable", "--only", "cpu"},
+		},
+		{
+			name:     "several columns",
+			input:    []string{"-o", "pid,user,%cpu,cmd"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
+			config:   []string{`kind = "Pid"`, `kind = "User"`, `kind = "UsageCpu"`, `kind = "Command"`},
+		},
+		{
+			name:     "repeated -o",
+			input:    []string{"-o", "pid", "-o", "rss"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
+			config:   []string{`kind = "Pid"`, `kind = "VmRss"`},
+		},
+		{
+			name:     "format option",
+			input:    []string{"--format=pid,vsz"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
+			config:   []string{`kind = "Pid"`, `kind = "VmSize"`},
+		},
+		{
+			name:     "format option with separate value",
+			input:    []string{"--format", "etime"},
+			expected: []string{"--pager", "disable", "--only", "elapsed"},
+		},
+		{
+			name:     "header rename",
+			input:    []string{"-o", "pid=PROCESS"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
+			config:   []string{`kind = "Pid"`, `header = "PROCESS"`},
+		},
+		{
+			name:     "header extends to end of list",
+			input:    []string{"-o", "user,args=COMMAND, LINE"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
+			config:   []string{`kind = "User"`, `kind = "Command"`, `header = "COMMAND, LINE"`},
+		},
+		{
+			name:     "blank separated columns",
+			input:    []string{"-o", "pid user"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
+			config:   []string{`kind = "Pid"`, `kind = "User"`},
+		},
+		{
+			name:     "unknown column warns",
+			input:    []string{"-o", "pid,wchan"},
+			expected: []string{"--pager", "disable", "--only", "pid"},
+			warns:    true,
+		},
+		{
+			name:     "O inserts columns",
+			input:    []string{"-O", "rss,%mem"},
+			expected: []string{"--pager", "disable", "--insert", "rss", "--insert", "mem"},
+		},
+		{
+			name:     "BSD o",
+			input:    []string{"axo", "pid,ppid"},
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
+			config:   []string{`kind = "Pid"`, `kind = "Ppid"`},
+		},
+		{
+			name:     "BSD O",
+			input:    []string{"O", "rss"},
+			expected: []string{"--pager", "disable", "--insert", "rss"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*config = ""
+			result := translateFlags(tt.input, ModeProcps)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			last := 0
+			for _, want := range tt.config {
+				idx := strings.Index((*config)[last:], want)
+				if idx == -1 {
+					t.Errorf("translateFlags(%v) config missing %q in order:\n%s", tt.input, want, *config)
+					break
+				}
+				last += idx + len(want)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	stubConfig(t)
+
+	tests := []struct {
+		name     string
+		input    []string
+		mode     PSMode
+		expected []string
+		warns    bool
+	}{
+		{
+			name:     "descending key",
+			input:    []string{"aux", "--sort=-rss"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "rss"},
+		},
+		{
+			name:     "separate value",
+			input:    []string{"--sort", "pid"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sorta", "pid"},
+		},
+		{
+			name:     "secondary keys are dropped",
+			input:    []string{"--sort=-%cpu,pid"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "cpu"},
+			warns:    true,
+		},
+		{
+			name:     "unknown primary key falls through to the next",
+			input:    []string{"--sort=wchan,+user"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sorta", "user"},
+			warns:    true,
+		},
+		{
+			name:     "BSD k attached",
+			input:    []string{"auxk-%mem"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "mem"},
+		},
+		{
+			name:     "BSD k in a later word",
+			input:    []string{"aux", "k-%mem"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "mem"},
+		},
+		{
+			name:     "BSD k with a separate spec",
+			input:    []string{"aux", "k", "-%mem"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "mem"},
+		},
+		{
+			name:     "BSD k in the options word",
+			input:    []string{"auxk", "-%mem,+pid"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "mem"},
+			warns:    true,
+		},
+		{
+			name:     "procps O with directions sorts",
+			input:    []string{"axO", "-rss"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "rss"},
+		},
+		{
+			name:     "procps O without directions inserts",
+			input:    []string{"axO", "rss"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--insert", "rss"},
+		},
+		{
+			name:     "BSD O always inserts",
+			input:    []string{"axO", "rss"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable", "--insert", "rss"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.mode)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+		})
+	}
+}
+
+func TestSelection(t *testing.T) {
+	config := stubConfig(t)
+
+	tests := []struct {
+		name     string
+		input    []string
+		mode     PSMode
+		expected []string
+		config   []string // expected config fragments, in order
+		fallback bool
+	}{
+		{
+			name:     "PID list",
+			input:    []string{"-p", "1,2 3"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1", "2", "3"},
+			config:   []string{"numeric_search = \"Exact\"", "kind = \"Pid\"\nstyle = \"BrightWhite|Black\"\nnumeric_search = true", "kind = \"Command\"\nstyle = \"BrightWhite|Black\"\nnumeric_search = false\nnonnumeric_search = false"},
+		},
+		{
+			name:     "user names and IDs",
+			input:    []string{"-u", "alice,1000"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "alice", "1000"},
+			config:   []string{"kind = \"User\"\nstyle = \"BrightWhite|Black\"\nnumeric_search = false\nnonnumeric_search = true", "kind = \"Uid\"\nstyle = \"BrightWhite|Black\"\nnumeric_search = true"},
+		},
+		{
+			name:     "selectors are combined",
+			input:    []string{"-C", "nginx", "-p1"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1", "nginx"},
+			config:   []string{`nonnumeric_search = "Partial"`},
+		},
+		{
+			name:     "long options",
+			input:    []string{"--pid=1", "--user", "root"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1", "root"},
+		},
+		{
+			name:     "numeric -g is a session",
+			input:    []string{"-g", "5"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "5"},
+			config:   []string{"kind = \"Session\"\nstyle = \"BrightWhite|Black\"\nnumeric_search = true"},
+		},
+		{
+			name:     "group names",
+			input:    []string{"-G", "wheel"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "wheel"},
+			config:   []string{"kind = \"Group\"\nstyle = \"BrightWhite|Black\"\nnumeric_search = false\nnonnumeric_search = true"},
+		},
+		{
+			name:     "tty numbers",
+			input:    []string{"-t", "1,/dev/pts/2"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "tty1", "pts/2"},
+		},
+		{
+			name:     "selection keeps the chosen columns",
+			input:    []string{"-o", "pid,comm", "-p", "1"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1"},
+			config:   []string{"kind = \"Pid\"", "kind = \"Command\""},
+		},
+		{
+			name:     "negated selection",
+			input:    []string{"-N", "-u", "root"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--nor", "root"},
+		},
+		{
+			name:     "deselect",
+			input:    []string{"--deselect", "-p", "1"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--nor", "1"},
+		},
+		{
+			name:     "negating the default selection falls back to ps",
+			input:    []string{"-N"},
+			mode:     ModeProcps,
+			fallback: true,
+		},
+		{
+			name:     "BSD -g takes no value",
+			input:    []string{"-g", "nginx"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable", "nginx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*config = ""
+			result := translateFlags(tt.input, tt.mode)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if result.Fallback != tt.fallback {
+				t.Errorf("translateFlags(%v) fallback = %v, want %v", tt.input, result.Fallback, tt.fallback)
+			}
+			rest := *config
+			for _, want := range tt.config {
+				idx := strings.Index(rest, want)
+				if idx == -1 {
+					t.Fatalf("translateFlags(%v) config missing %q in order:\n%s", tt.input, want, *config)
+				}
+				rest = rest[idx+len(want):]
+			}
+		})
+	}
+}
+
+func TestThreadsAndJobFormat(t *testing.T) {
+	config := stubConfig(t)
+
+	tests := []struct {
+		name     string
+		input    []string
+		mode     PSMode
+		expected []string
+		config   []string // expected config kinds, in order
+		warns    bool
+	}{
+		{
+			name:     "threads with -L",
+			input:    []string{"-eLf"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--thread"},
+		},
+		{
+			name:     "threads of a process with -T",
+			input:    []string{"-T", "-p", "42"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--thread", "--or", "42"},
+		},
+		{
+			name:     "threads after processes with -m",
+			input:    []string{"-m"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--thread"},
+		},
+		{
+			name:     "BSD H",
+			input:    []string{"H"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--thread"},
+		},
+		{
+			name:     "BSD m",
+			input:    []string{"axm"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--thread"},
+		},
+		{
+			name:     "BSD -M can't show threads",
+			input:    []string{"-M"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable"},
+			warns:    true,
+		},
+		{
+			name:     "BSD -C is the CPU calculation",
+			input:    []string{"-C", "aux"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable"},
+		},
+		{
+			name:     "BSD -C with a search term",
+			input:    []string{"-C", "nginx"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable", "nginx"},
+		},
+		{
+			name:     "BSD -T is this terminal",
+			input:    []string{"-T"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable"},
+		},
+		{
+			name:     "job format tree",
+			input:    []string{"-ejH"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--tree", "--load-config", "procs.toml"},
+			config:   []string{"Pid", "Pgid", "Session", "Tty", "CpuTime", "Command"},
+		},
+		{
+			name:     "BSD job format forest",
+			input:    []string{"axjf"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--tree", "--load-config", "procs.toml"},
+			config:   []string{"Ppid", "Pid", "Pgid", "Session", "Tty", "State", "Uid", "CpuTime", "Command"},
+		},
+		{
+			name:     "explicit columns replace the job format",
+			input:    []string{"-ejH", "-o", "pid,nlwp"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--tree", "--load-config", "procs.toml"},
+			config:   []string{"Pid", "Threads"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*config = ""
+			result := translateFlags(tt.input, tt.mode)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+			var kinds []string
+			for _, line := range strings.Split(*config, "\n") {
+				if kind, ok := strings.CutPrefix(line, "kind = "); ok {
+					kinds = append(kinds, strings.Trim(kind, `"`))
+				}
+			}
+			if tt.config != nil && !reflect.DeepEqual(kinds, tt.config) {
+				t.Errorf("translateFlags(%v) config kinds = %v, want %v", tt.input, kinds, tt.config)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	config := stubConfig(t)
+
+	tests := []struct {
+		name     string
+		sel      Selection
+		expected []string
+		config   []string // expected config fragments, in order
+	}{
+		{
+			name:     "nothing selected",
+			sel:      Selection{Negate: true},
+			expected: nil,
+		},
+		{
+			name:     "any keyword",
+			sel:      Selection{PIDs: []string{"1,2"}, Users: []string{"root"}},
+			expected: []string{"--load-config", "procs.toml", "--or", "1", "2", "root"},
+		},
+		{
+			name:     "every keyword",
+			sel:      Selection{Users: []string{"root"}, Commands: []string{"nginx: master"}, Partial: true, MatchAll: true},
+			expected: []string{"--load-config", "procs.toml", "--and", "root", "nginx: master"},
+			config:   []string{`nonnumeric_search = "Partial"`},
+		},
+		{
+			name:     "negated",
+			sel:      Selection{Commands: []string{"a", "b"}, MatchAll: true, Negate: true, IgnoreCase: true},
+			expected: []string{"--load-config", "procs.toml", "--nand", "a", "b"},
+			config:   []string{`nonnumeric_search = "Partial"`, `case = "Insensitive"`},
+		},
+		{
+			name:     "users are exact",
+			sel:      Selection{Users: []string{"root"}},
+			expected: []string{"--load-config", "procs.toml", "--or", "root"},
+			config:   []string{`nonnumeric_search = "Exact"`},
+		},
+		{
+			name:     "format",
+			sel:      Selection{PPIDs: []string{"1"}, Format: "pid,comm"},
+			expected: []string{"--load-config", "procs.toml", "--or", "1"},
+			config:   []string{"kind = \"Pid\"", "kind = \"Command\"", "kind = \"Ppid\""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*config = ""
+			var warnings []string
+			result := Select(tt.sel, &warnings)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Select(%+v) = %v, want %v", tt.sel, result, tt.expected)
+			}
+			rest := *config
+			for _, want := range tt.config {
+				idx := strings.Index(rest, want)
+				if idx == -1 {
+					t.Fatalf("Select(%+v) config missing %q in order:\n%s", tt.sel, want, *config)
+				}
+				rest = rest[idx+len(want):]
+			}
+		})
+	}
+}
+
+func TestWriteCachedConfig(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	content := columnsConfig([]column{{kind: "pid"}, {kind: "command"}}, nil)
+	path, err := writeCachedConfig(content)
+	if err != nil {
+		t.Fatalf("writeCachedConfig() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	if string(data) != content {
+		t.Errorf("config = %q, want %q", data, content)
+	}
+
+	again, err := writeCachedConfig(content)
+	if err != nil || again != path {
+		t.Errorf("writeCachedConfig() again = %q, %v, want %q", again, err, path)
+	}
+}
+
 func TestIsBSDStyleOptions(t *testing.T) {
 	tests := []struct {
 		input    string
+		mode     PSMode
 		expected bool
 	}{
-		{"aux", true},
-		{"ef", true},
-		{"axjf", true},
-		{"ax", true},
-		{"u", true},
-		{"nginx", false},   // no common BSD chars (auxef)
-		{"1234", false},    // looks like a PID
-		{"toolong", false}, // too many chars
-		{"root", false},    // no common BSD chars
-		{"ps", false},      // no common BSD chars
+		{"aux", ModeProcps, true},
+		{"ef", ModeProcps, true},
+		{"axjf", ModeProcps, true},
+		{"ax", ModeProcps, true},
+		{"u", ModeProcps, true},
+		{"java", ModeProcps, true},        // every letter is an option, as in ps
+		{"axopid,comm", ModeProcps, true}, // attached format list
+		{"p1234", ModeProcps, true},       // attached PID list
+		{"k-%cpu", ModeProcps, true},      // attached sort keys
+		{"Ouser", ModeProcps, true},       // attached insert list
+		{"tpts/1", ModeProcps, true},      // attached tty
+		{"nginx", ModeProcps, false},      // i is not an option
+		{"1234", ModeProcps, false},       // a PID
+		{"toolong", ModeProcps, false},    // "oolong" is not a tty
+		{"root", ModeProcps, false},       // "ot" is not a format
+		{"htop", ModeProcps, false},       // "op" is not a tty
+		{"ps", ModeProcps, false},         // "s" is not a PID list
+		{"sed", ModeProcps, false},        // d is not an option
+		{"vim", ModeProcps, false},        // i is not an option
+		{"node", ModeProcps, false},       // "de" is not a format
+		{"Uroot", ModeProcps, false},      // user names are never attached
+		{"axd", ModeBSD, true},            // d is a BSD option
+		{"aux", ModeBSD, true},
+		{"k-%cpu", ModeBSD, false}, // k is not a BSD option
+		{"nginx", ModeBSD, false},
+		{"", ModeProcps, false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := isBSDStyleOptions(tt.input)
+			result := isBSDStyleOptions(tt.input, tt.mode)
 			if result != tt.expected {
-				t.Errorf("isBSDStyleOptions(%q) = %v, want %v", tt.input, result, tt.expected)
+				t.Errorf("isBSDStyleOptions(%q, %v) = %v, want %v", tt.input, tt.mode, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDisambiguation(t *testing.T) {
+	stubConfig(t)
+
+	tests := []struct {
+		name     string
+		input    []string
+		mode     PSMode
+		expected []string
+	}{
+		{
+			name:     "only the first word can be BSD options",
+			input:    []string{"aux", "ax"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "ax"},
+		},
+		{
+			name:     "search term after BSD options",
+			input:    []string{"aux", "less"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "less"},
+		},
+		{
+			name:     "search term that looks like job format",
+			input:    []string{"aux", "java"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "java"},
+		},
+		{
+			name:     "search term after UNIX options",
+			input:    []string{"-ef", "cat"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "cat"},
+		},
+		{
+			name:     "later format option",
+			input:    []string{"ax", "o", "pid,comm"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml"},
+		},
+		{
+			name:     "first word after dash options",
+			input:    []string{"-e", "aux"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable"},
+		},
+		{
+			name:     "search term then more words",
+			input:    []string{"root", "aux"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "root", "aux"},
+		},
+		{
+			name:     "dashed BSD options",
+			input:    []string{"-aux"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable"},
+		},
+		{
+			name:     "dashed BSD options with a sort",
+			input:    []string{"-auxk-rss"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "rss"},
+		},
+		{
+			name:     "-u with a user name",
+			input:    []string{"-uroot"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root"},
+		},
+		{
+			name:     "BSD -aux is UNIX options",
+			input:    []string{"-aux"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable"},
+		},
+		{
+			name:     "option values are not options",
+			input:    []string{"-u", "root", "ax"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root"},
+		},
+		{
+			name:     "BSD p with separate PID",
+			input:    []string{"up", "1234"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1234"},
+		},
+		{
+			name:     "BSD p with attached PIDs",
+			input:    []string{"p1234"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "1234"},
+		},
+		{
+			name:     "BSD U takes a user",
+			input:    []string{"U", "root"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root"},
+		},
+		{
+			name:     "BSD k sorts",
+			input:    []string{"axk-%mem"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--sortd", "mem"},
+		},
+		{
+			name:     "BSD o takes a format",
+			input:    []string{"axo", "pid"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "--only", "pid"},
+		},
+		{
+			name:     "ambiguous name htop",
+			input:    []string{"htop"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "htop"},
+		},
+		{
+			name:     "ambiguous name sed",
+			input:    []string{"sed"},
+			mode:     ModeProcps,
+			expected: []string{"--pager", "disable", "sed"},
+		},
+		{
+			name:     "BSD f is not a tree on macOS",
+			input:    []string{"axf"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable"},
+		},
+		{
+			name:     "BSD d is a tree",
+			input:    []string{"axd"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable", "--tree"},
+		},
+		{
+			name:     "BSD m and r sort",
+			input:    []string{"aux", "-m"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable", "--sortd", "mem"},
+		},
+		{
+			name:     "BSD -u is a format",
+			input:    []string{"-u"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable"},
+		},
+		{
+			name:     "BSD -U is a user",
+			input:    []string{"-U", "root"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable", "--load-config", "procs.toml", "--or", "root"},
+		},
+		{
+			name:     "BSD -r sorts by CPU",
+			input:    []string{"-axr"},
+			mode:     ModeBSD,
+			expected: []string{"--pager", "disable", "--sortd", "cpu"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(tt.input, tt.mode).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("translateFlags(%v, %v) = %v, want %v", tt.input, tt.mode, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetPSMode(t *testing.T) {
+	tests := []struct {
+		mode     string
+		expected PSMode
+	}{
+		{"bsd", ModeBSD},
+		{"BSD", ModeBSD},
+		{"gnu", ModeProcps},
+		{"procps", ModeProcps},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if result := GetPSMode(tt.mode); result != tt.expected {
+				t.Errorf("GetPSMode(%q) = %v, want %v", tt.mode, result, tt.expected)
 			}
 		})
 	}
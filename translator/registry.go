@@ -34,6 +34,21 @@ func GetByName(name string) Translator {
 	return registry[name]
 }
 
+// ForSource returns the translators for a source tool, sorted by name
+// Several translators can share a source tool (e.g., ls2eza and ls2lsd)
+func ForSource(source string) []Translator {
+	mu.RLock()
+	defer mu.RUnlock()
+	var matches []Translator
+	for _, t := range registry {
+		if t.SourceTool() == source {
+			matches = append(matches, t)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Name() < matches[j].Name() })
+	return matches
+}
+
 // List returns all registered translator names
 func List() []string {
 	mu.RLock()
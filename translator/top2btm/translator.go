@@ -0,0 +1,57 @@
+package top2btm
+
+import (
+	"strconv"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ps2procs"
+	"github.com/kluzzebass/reflag/translator/top2procs"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the top to bottom (btm) flag translation
+// It reuses top2procs' knowledge of top's flags
+type Translator struct{}
+
+func (t *Translator) Name() string        { return "top2btm" }
+func (t *Translator) SourceTool() string  { return "top" }
+func (t *Translator) TargetTool() string  { return "btm" }
+func (t *Translator) IncludeInInit() bool { return false }
+
+// Translate converts top arguments to btm arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translateFlags(top2procs.ParseFlags(args, ps2procs.GetPSMode(opts.Mode)))
+}
+
+func translateFlags(f top2procs.TopFlags) translator.Result {
+	// bottom is interactive only, so batch output and single screens have to
+	// come from top
+	if f.Fallback || f.Batch || f.Iterations == 1 {
+		return translator.Result{Fallback: true}
+	}
+
+	// Open on the process list, which is what top shows
+	btmArgs := []string{"--default_widget_type", "proc"}
+	warnings := f.Warnings
+
+	if f.Delay > 0 {
+		// bottom's refresh rate is in milliseconds
+		btmArgs = append(btmArgs, "--rate", strconv.FormatInt(int64(f.Delay*1000), 10))
+	}
+	if f.Iterations > 0 {
+		warnings = append(warnings, "bottom runs until you quit, ignoring the iteration count")
+	}
+	if f.Sort != "" {
+		warnings = append(warnings, "bottom can't sort from the command line, ignoring the sort field")
+	}
+	if len(f.Users) > 0 || len(f.PIDs) > 0 {
+		warnings = append(warnings, "bottom can't filter processes from the command line, search with / instead")
+	}
+	if f.Threads {
+		warnings = append(warnings, "bottom can't show threads")
+	}
+	return translator.Result{Args: btmArgs, Warnings: warnings}
+}
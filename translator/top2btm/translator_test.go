@@ -0,0 +1,118 @@
+package top2btm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ps2procs"
+	"github.com/kluzzebass/reflag/translator/top2procs"
+)
+
+func TestTranslateFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		mode     ps2procs.PSMode
+		expected []string
+		warns    bool
+		fallback bool
+	}{
+		{
+			name:     "no args",
+			input:    []string{},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--default_widget_type", "proc"},
+		},
+		{
+			name:     "delay in milliseconds",
+			input:    []string{"-d", "1.5"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--default_widget_type", "proc", "--rate", "1500"},
+		},
+		{
+			name:     "BSD delay",
+			input:    []string{"-s", "2"},
+			mode:     ps2procs.ModeBSD,
+			expected: []string{"--default_widget_type", "proc", "--rate", "2000"},
+		},
+		{
+			name:     "sort field",
+			input:    []string{"-o", "%MEM"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--default_widget_type", "proc"},
+			warns:    true,
+		},
+		{
+			name:     "user filter",
+			input:    []string{"-u", "alice"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--default_widget_type", "proc"},
+			warns:    true,
+		},
+		{
+			name:     "threads",
+			input:    []string{"-H"},
+			mode:     ps2procs.ModeProcps,
+			expected: []string{"--default_widget_type", "proc"},
+			warns:    true,
+		},
+		{
+			name:     "batch mode runs top",
+			input:    []string{"-b", "-n1"},
+			mode:     ps2procs.ModeProcps,
+			fallback: true,
+		},
+		{
+			name:     "one iteration runs top",
+			input:    []string{"-n1"},
+			mode:     ps2procs.ModeProcps,
+			fallback: true,
+		},
+		{
+			name:     "BSD logging mode runs top",
+			input:    []string{"-l", "1"},
+			mode:     ps2procs.ModeBSD,
+			fallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translateFlags(top2procs.ParseFlags(tt.input, tt.mode))
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+			if result.Fallback != tt.fallback {
+				t.Errorf("translateFlags(%v) fallback = %v, want %v", tt.input, result.Fallback, tt.fallback)
+			}
+		})
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "top2btm" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "top2btm")
+	}
+	if tr.SourceTool() != "top" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "top")
+	}
+	if tr.TargetTool() != "btm" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "btm")
+	}
+	if tr.IncludeInInit() {
+		t.Error("IncludeInInit() = true, want false")
+	}
+
+	// Test translation via interface
+	result := tr.Translate([]string{"-d", "2"}, translator.Options{Mode: "procps"}).Args
+	expected := []string{"--default_widget_type", "proc", "--rate", "2000"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Translate(-d 2) = %v, want %v", result, expected)
+	}
+}
@@ -0,0 +1,255 @@
+package top2procs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ps2procs"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the top to procs flag translation
+type Translator struct{}
+
+func (t *Translator) Name() string        { return "top2procs" }
+func (t *Translator) SourceTool() string  { return "top" }
+func (t *Translator) TargetTool() string  { return "procs" }
+func (t *Translator) IncludeInInit() bool { return true }
+
+// Translate converts top arguments to procs arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return translateFlags(ParseFlags(args, ps2procs.GetPSMode(opts.Mode)))
+}
+
+// TopFlags holds the top options that a process monitor can honor
+type TopFlags struct {
+	Batch      bool     // batch (procps -b) or logging (BSD -l) mode
+	Iterations int      // -n (procps) or -l (BSD) sample count, 0 for no limit
+	Delay      float64  // refresh interval in seconds, 0 for the default
+	Users      []string // -u/-U users
+	PIDs       []string // -p PIDs
+	Sort       string   // procs sort column, "" for top's default of CPU usage
+	Ascending  bool     // sort in ascending order
+	Threads    bool     // show threads (procps -H)
+	Fallback   bool     // informational options only top itself can answer
+	Warnings   []string // options that were dropped
+}
+
+// procps top options that take a value, attached or in the next argument
+const procpsValueLetters = "dnuUpoEe"
+
+// BSD (macOS) top options that take a value
+const bsdValueLetters = "cilnoOsU"
+
+// Column names top uses that differ from ps
+var topColumns = map[string]string{
+	"res":   "rss",
+	"rsize": "rss",
+	"virt":  "vsz",
+	"vsize": "vsz",
+	"time+": "time",
+	"pr":    "priority",
+	"s":     "state",
+	"nth":   "threads",
+	"th":    "threads",
+	"pgrp":  "pgid",
+}
+
+// ParseFlags parses procps or BSD top arguments
+func ParseFlags(args []string, mode ps2procs.PSMode) TopFlags {
+	var f TopFlags
+	valueLetters := procpsValueLetters
+	if mode == ps2procs.ModeBSD {
+		valueLetters = bsdValueLetters
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// BSD top has a few multi-letter options
+		if mode == ps2procs.ModeBSD {
+			switch arg {
+			case "-pid":
+				if i+1 < len(args) {
+					f.PIDs = append(f.PIDs, args[i+1])
+					i++
+				}
+				continue
+			case "-stats", "-ncols":
+				if i+1 < len(args) {
+					i++
+				}
+				f.Warnings = append(f.Warnings, fmt.Sprintf("ignoring %s", arg))
+				continue
+			}
+		}
+
+		if !strings.HasPrefix(arg, "-") || len(arg) < 2 {
+			f.Warnings = append(f.Warnings, fmt.Sprintf("ignoring %s", arg))
+			continue
+		}
+
+		letters := arg[1:]
+		for j, c := range letters {
+			if c == 'w' && mode == ps2procs.ModeProcps {
+				// Output width, with an optional attached value
+				break
+			}
+			if !strings.ContainsRune(valueLetters, c) {
+				f.flag(c, mode)
+				continue
+			}
+
+			// The value is the rest of the argument or the next one
+			val := letters[j+1:]
+			if val == "" && i+1 < len(args) {
+				val = args[i+1]
+				i++
+			}
+			f.value(c, val, mode)
+			break
+		}
+	}
+	return f
+}
+
+// flag handles a top option without a value
+func (f *TopFlags) flag(c rune, mode ps2procs.PSMode) {
+	if mode == ps2procs.ModeBSD {
+		switch c {
+		case 'u': // alias for -o cpu -O time
+			f.Sort, f.Ascending = "cpu", false
+		case 'h':
+			f.Fallback = true
+		case 'a', 'd', 'e', 'F', 'f', 'R', 'r', 'S':
+			// Event counting modes and framework sampling
+		default:
+			f.Warnings = append(f.Warnings, fmt.Sprintf("ignoring -%c", c))
+		}
+		return
+	}
+
+	switch c {
+	case 'b': // batch mode
+		f.Batch = true
+	case 'H': // threads
+		f.Threads = true
+	case 'h', 'v', 'O': // help, version and field names
+		f.Fallback = true
+	case 'c', 'i', 's', 'S', '1':
+		// Display toggles procs doesn't need
+	default:
+		f.Warnings = append(f.Warnings, fmt.Sprintf("ignoring -%c", c))
+	}
+}
+
+// value handles a top option with a value
+func (f *TopFlags) value(c rune, val string, mode ps2procs.PSMode) {
+	switch {
+	case c == 'd' || c == 's' && mode == ps2procs.ModeBSD: // delay
+		delay, err := strconv.ParseFloat(val, 64)
+		if err != nil || delay <= 0 {
+			f.Warnings = append(f.Warnings, fmt.Sprintf("invalid delay %q", val))
+			return
+		}
+		f.Delay = delay
+	case c == 'n' && mode == ps2procs.ModeProcps, c == 'l': // iterations, or BSD logging samples
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			f.Warnings = append(f.Warnings, fmt.Sprintf("invalid count %q", val))
+			return
+		}
+		f.Iterations = n
+		if c == 'l' {
+			f.Batch = true
+		}
+	case c == 'u' || c == 'U':
+		f.Users = append(f.Users, val)
+	case c == 'p':
+		f.PIDs = append(f.PIDs, val)
+	case c == 'o':
+		f.sortKey(val, mode)
+	case c == 'O' && mode == ps2procs.ModeBSD:
+		f.Warnings = append(f.Warnings, fmt.Sprintf("procs sorts by one column, ignoring -O %s", val))
+	case c == 'n': // BSD: number of processes to show
+		f.Warnings = append(f.Warnings, fmt.Sprintf("procs can't limit the number of processes, ignoring -n %s", val))
+	}
+	// -E, -e, -c and -i only change top's display
+}
+
+// sortKey sets the sort column from a top field name
+// procps top sorts high to low unless the field has a - prefix, while BSD
+// top sorts in descending order unless it has a + prefix
+func (f *TopFlags) sortKey(key string, mode ps2procs.PSMode) {
+	name := strings.TrimLeft(key, "+-")
+	kind, ok := topColumns[strings.ToLower(name)]
+	if ok {
+		kind, ok = ps2procs.Column(kind)
+	} else {
+		kind, ok = ps2procs.Column(name)
+	}
+	if !ok {
+		f.Warnings = append(f.Warnings, fmt.Sprintf("procs can't sort by %s", name))
+		return
+	}
+	f.Sort = kind
+	if mode == ps2procs.ModeBSD {
+		f.Ascending = strings.HasPrefix(key, "+")
+	} else {
+		f.Ascending = strings.HasPrefix(key, "-")
+	}
+}
+
+// FormatDelay formats a delay in seconds without a trailing fraction
+func FormatDelay(delay float64) string {
+	return strconv.FormatFloat(delay, 'f', -1, 64)
+}
+
+func translateFlags(f TopFlags) translator.Result {
+	if f.Fallback {
+		return translator.Result{Fallback: true}
+	}
+
+	var procsArgs []string
+	warnings := f.Warnings
+
+	if f.Batch || f.Iterations == 1 {
+		// procs prints a single listing, which is what top -b -n1 is used for,
+		// and top -n1 shows one screen and exits
+		procsArgs = append(procsArgs, "--pager", "disable")
+		if f.Iterations != 1 {
+			warnings = append(warnings, "procs prints one listing in batch mode")
+		}
+	} else {
+		if f.Delay > 0 {
+			procsArgs = append(procsArgs, "--watch-interval", FormatDelay(f.Delay))
+		} else {
+			procsArgs = append(procsArgs, "--watch")
+		}
+		if f.Iterations > 0 {
+			warnings = append(warnings, "procs watches until you quit, ignoring the iteration count")
+		}
+	}
+
+	// top sorts by CPU usage by default
+	switch {
+	case f.Sort == "":
+		procsArgs = append(procsArgs, "--sortd", "cpu")
+	case f.Ascending:
+		procsArgs = append(procsArgs, "--sorta", f.Sort)
+	default:
+		procsArgs = append(procsArgs, "--sortd", f.Sort)
+	}
+
+	if f.Threads {
+		procsArgs = append(procsArgs, "--thread")
+	}
+
+	procsArgs = append(procsArgs, ps2procs.Select(ps2procs.Selection{PIDs: f.PIDs, Users: f.Users}, &warnings)...)
+	return translator.Result{Args: procsArgs, Warnings: warnings}
+}
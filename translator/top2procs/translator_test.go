@@ -0,0 +1,230 @@
+package top2procs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/ps2procs"
+)
+
+// translate runs a translation with generated procs configs kept out of the
+// user's cache, and replaces their paths with "procs.toml"
+func translate(t *testing.T, args []string, mode ps2procs.PSMode) translator.Result {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	result := translateFlags(ParseFlags(args, mode))
+	for i := 1; i < len(result.Args); i++ {
+		if result.Args[i-1] == "--load-config" {
+			result.Args[i] = "procs.toml"
+		}
+	}
+	return result
+}
+
+func TestTranslateFlagsProcps(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+		warns    bool
+	}{
+		{
+			name:     "no args",
+			input:    []string{},
+			expected: []string{"--watch", "--sortd", "cpu"},
+		},
+		{
+			name:     "delay",
+			input:    []string{"-d", "2"},
+			expected: []string{"--watch-interval", "2", "--sortd", "cpu"},
+		},
+		{
+			name:     "attached fractional delay",
+			input:    []string{"-d0.5"},
+			expected: []string{"--watch-interval", "0.5", "--sortd", "cpu"},
+		},
+		{
+			name:     "invalid delay",
+			input:    []string{"-d", "soon"},
+			expected: []string{"--watch", "--sortd", "cpu"},
+			warns:    true,
+		},
+		{
+			name:     "one-shot batch",
+			input:    []string{"-b", "-n1"},
+			expected: []string{"--pager", "disable", "--sortd", "cpu"},
+		},
+		{
+			name:     "combined batch flags",
+			input:    []string{"-bn", "1"},
+			expected: []string{"--pager", "disable", "--sortd", "cpu"},
+		},
+		{
+			name:     "endless batch",
+			input:    []string{"-b"},
+			expected: []string{"--pager", "disable", "--sortd", "cpu"},
+			warns:    true,
+		},
+		{
+			name:     "one iteration without batch",
+			input:    []string{"-n", "1"},
+			expected: []string{"--pager", "disable", "--sortd", "cpu"},
+		},
+		{
+			name:     "iterations without batch",
+			input:    []string{"-n", "3"},
+			expected: []string{"--watch", "--sortd", "cpu"},
+			warns:    true,
+		},
+		{
+			name:     "sort by memory",
+			input:    []string{"-o", "%MEM"},
+			expected: []string{"--watch", "--sortd", "mem"},
+		},
+		{
+			name:     "low to high",
+			input:    []string{"-o", "-PID"},
+			expected: []string{"--watch", "--sorta", "pid"},
+		},
+		{
+			name:     "top field names",
+			input:    []string{"-oRES"},
+			expected: []string{"--watch", "--sortd", "rss"},
+		},
+		{
+			name:     "unknown sort field",
+			input:    []string{"-o", "SWAP"},
+			expected: []string{"--watch", "--sortd", "cpu"},
+			warns:    true,
+		},
+		{
+			name:     "user",
+			input:    []string{"-u", "alice"},
+			expected: []string{"--watch", "--sortd", "cpu", "--load-config", "procs.toml", "--or", "alice"},
+		},
+		{
+			name:     "PIDs",
+			input:    []string{"-p", "123,456", "-p789"},
+			expected: []string{"--watch", "--sortd", "cpu", "--load-config", "procs.toml", "--or", "123", "456", "789"},
+		},
+		{
+			name:     "threads",
+			input:    []string{"-H"},
+			expected: []string{"--watch", "--sortd", "cpu", "--thread"},
+		},
+		{
+			name:     "display toggles",
+			input:    []string{"-ci", "-w", "-E", "g"},
+			expected: []string{"--watch", "--sortd", "cpu"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translate(t, tt.input, ps2procs.ModeProcps)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+		})
+	}
+}
+
+func TestTranslateFlagsBSD(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+		warns    bool
+	}{
+		{
+			name:     "delay",
+			input:    []string{"-s", "5"},
+			expected: []string{"--watch-interval", "5", "--sortd", "cpu"},
+		},
+		{
+			name:     "one sample",
+			input:    []string{"-l", "1"},
+			expected: []string{"--pager", "disable", "--sortd", "cpu"},
+		},
+		{
+			name:     "sort descending by default",
+			input:    []string{"-o", "rsize"},
+			expected: []string{"--watch", "--sortd", "rss"},
+		},
+		{
+			name:     "ascending sort",
+			input:    []string{"-o", "+pid"},
+			expected: []string{"--watch", "--sorta", "pid"},
+		},
+		{
+			name:     "secondary key",
+			input:    []string{"-o", "cpu", "-O", "time"},
+			expected: []string{"--watch", "--sortd", "cpu"},
+			warns:    true,
+		},
+		{
+			name:     "u sorts by CPU",
+			input:    []string{"-u"},
+			expected: []string{"--watch", "--sortd", "cpu"},
+		},
+		{
+			name:     "user and pid",
+			input:    []string{"-U", "alice", "-pid", "42"},
+			expected: []string{"--watch", "--sortd", "cpu", "--load-config", "procs.toml", "--or", "42", "alice"},
+		},
+		{
+			name:     "process count",
+			input:    []string{"-n", "10"},
+			expected: []string{"--watch", "--sortd", "cpu"},
+			warns:    true,
+		},
+		{
+			name:     "stats",
+			input:    []string{"-stats", "pid,command"},
+			expected: []string{"--watch", "--sortd", "cpu"},
+			warns:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := translate(t, tt.input, ps2procs.ModeBSD)
+			if !reflect.DeepEqual(result.Args, tt.expected) {
+				t.Errorf("translateFlags(%v) = %v, want %v", tt.input, result.Args, tt.expected)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.warns {
+				t.Errorf("translateFlags(%v) warnings = %v, want warnings: %v", tt.input, result.Warnings, tt.warns)
+			}
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	for _, args := range [][]string{{"-h"}, {"-v"}, {"-O"}} {
+		if result := translate(t, args, ps2procs.ModeProcps); !result.Fallback {
+			t.Errorf("translateFlags(%v) fallback = false, want true", args)
+		}
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "top2procs" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "top2procs")
+	}
+	if tr.SourceTool() != "top" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "top")
+	}
+	if tr.TargetTool() != "procs" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "procs")
+	}
+	if !tr.IncludeInInit() {
+		t.Error("IncludeInInit() = false, want true")
+	}
+}
@@ -1,5 +1,41 @@
 package translator
 
+// Options carries details about the invocation that can affect translation
+type Options struct {
+	// Mode allows dialect selection (e.g., "bsd" or "gnu" for ls2eza)
+	// An empty mode means auto-detect
+	Mode string
+
+	// Piped is true when the wrapped command's stdout is not a terminal
+	// The shell wrapper reports this, since reflag's own stdout is always captured
+	Piped bool
+
+	// Env holds the environment variables visible to the wrapped command
+	Env map[string]string
+}
+
+// Getenv returns the value of an environment variable, or "" if it's unset
+func (o Options) Getenv(key string) string {
+	return o.Env[key]
+}
+
+// Result is the outcome of a translation
+type Result struct {
+	// Args are the target tool arguments
+	Args []string
+
+	// Env holds environment variables to set for the target tool
+	Env map[string]string
+
+	// Warnings describe source options that couldn't be translated exactly
+	// They're reported on stderr so they don't end up in the evaluated command
+	Warnings []string
+
+	// Fallback is set when the target tool can't express the source options
+	// The original command is run unchanged instead
+	Fallback bool
+}
+
 // Translator defines the interface for converting flags between tools
 type Translator interface {
 	// Name returns the translator identifier (e.g., "ls2eza")
@@ -12,8 +48,7 @@ type Translator interface {
 	TargetTool() string
 
 	// Translate converts source tool arguments to target tool arguments
-	// The mode parameter allows dialect selection (e.g., "bsd" or "gnu" for ls2eza)
-	Translate(args []string, mode string) []string
+	Translate(args []string, opts Options) Result
 
 	// IncludeInInit returns true if this translator should be included in --init by default
 	// Translators returning false can still be explicitly included via --init <translator>
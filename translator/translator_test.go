@@ -10,18 +10,18 @@ type mockTranslator struct {
 	source        string
 	target        string
 	includeInInit bool
-	translateFn   func([]string, string) []string
+	translateFn   func([]string, Options) []string
 }
 
 func (m *mockTranslator) Name() string        { return m.name }
 func (m *mockTranslator) SourceTool() string  { return m.source }
 func (m *mockTranslator) TargetTool() string  { return m.target }
 func (m *mockTranslator) IncludeInInit() bool { return m.includeInInit }
-func (m *mockTranslator) Translate(args []string, mode string) []string {
+func (m *mockTranslator) Translate(args []string, opts Options) Result {
 	if m.translateFn != nil {
-		return m.translateFn(args, mode)
+		return Result{Args: m.translateFn(args, opts)}
 	}
-	return args
+	return Result{Args: args}
 }
 
 func TestTranslatorIncludeInInit(t *testing.T) {
@@ -61,7 +61,7 @@ func TestTranslatorInterface(t *testing.T) {
 		source:        "test",
 		target:        "test",
 		includeInInit: true,
-		translateFn: func(args []string, mode string) []string {
+		translateFn: func(args []string, opts Options) []string {
 			return append([]string{"--translated"}, args...)
 		},
 	}
@@ -93,7 +93,7 @@ func TestTranslatorInterface(t *testing.T) {
 	t.Run("Translate", func(t *testing.T) {
 		args := []string{"arg1", "arg2"}
 		want := []string{"--translated", "arg1", "arg2"}
-		if got := tr.Translate(args, ""); !equalSlices(got, want) {
+		if got := tr.Translate(args, Options{}).Args; !equalSlices(got, want) {
 			t.Errorf("Translate() = %v, want %v", got, want)
 		}
 	})
@@ -175,6 +175,24 @@ func TestRegistryWithIncludeInInit(t *testing.T) {
 	})
 }
 
+func TestForSource(t *testing.T) {
+	Register(&mockTranslator{name: "multi2zeta", source: "multi", target: "zeta"})
+	Register(&mockTranslator{name: "multi2alpha", source: "multi", target: "alpha"})
+
+	var names []string
+	for _, tr := range ForSource("multi") {
+		names = append(names, tr.Name())
+	}
+	want := []string{"multi2alpha", "multi2zeta"}
+	if !equalSlices(names, want) {
+		t.Errorf("ForSource(multi) = %v, want %v", names, want)
+	}
+
+	if got := ForSource("nonexistent"); len(got) != 0 {
+		t.Errorf("ForSource(nonexistent) = %v, want none", got)
+	}
+}
+
 func equalSlices(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -0,0 +1,24 @@
+package zegrep2rg
+
+import (
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/grep2rg"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the zegrep to ripgrep flag translation
+// zegrep is zgrep -E, whose syntax rg uses by default
+type Translator struct{}
+
+func (t *Translator) Name() string        { return "zegrep2rg" }
+func (t *Translator) SourceTool() string  { return "zegrep" }
+func (t *Translator) TargetTool() string  { return "rg" }
+func (t *Translator) IncludeInInit() bool { return true }
+
+// Translate converts zegrep arguments to ripgrep arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return grep2rg.TranslateVariant(args, opts, grep2rg.Variant{Decompress: true, Extended: true})
+}
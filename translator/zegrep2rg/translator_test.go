@@ -0,0 +1,54 @@
+package zegrep2rg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "searches compressed files",
+			input:    []string{"warn|error", "app.log.gz"},
+			expected: []string{"-z", "--max-depth=0", "warn|error", "app.log.gz"},
+		},
+		{
+			name:     "redundant E ignored",
+			input:    []string{"-E", "-n", "err(or)?", "app.log.gz"},
+			expected: []string{"-z", "-n", "--max-depth=0", "err(or)?", "app.log.gz"},
+		},
+	}
+
+	tr := &Translator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tr.Translate(tt.input, translator.Options{Mode: "gnu"}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Translate(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "zegrep2rg" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "zegrep2rg")
+	}
+	if tr.SourceTool() != "zegrep" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "zegrep")
+	}
+	if tr.TargetTool() != "rg" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "rg")
+	}
+	if !tr.IncludeInInit() {
+		t.Error("IncludeInInit() = false, want true")
+	}
+}
@@ -0,0 +1,24 @@
+package zgrep2rg
+
+import (
+	"github.com/kluzzebass/reflag/translator"
+	"github.com/kluzzebass/reflag/translator/grep2rg"
+)
+
+func init() {
+	translator.Register(&Translator{})
+}
+
+// Translator implements the zgrep to ripgrep flag translation
+// zgrep searches compressed files, which rg does with -z
+type Translator struct{}
+
+func (t *Translator) Name() string        { return "zgrep2rg" }
+func (t *Translator) SourceTool() string  { return "zgrep" }
+func (t *Translator) TargetTool() string  { return "rg" }
+func (t *Translator) IncludeInInit() bool { return true }
+
+// Translate converts zgrep arguments to ripgrep arguments
+func (t *Translator) Translate(args []string, opts translator.Options) translator.Result {
+	return grep2rg.TranslateVariant(args, opts, grep2rg.Variant{Decompress: true})
+}
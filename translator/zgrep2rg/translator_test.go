@@ -0,0 +1,59 @@
+package zgrep2rg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/reflag/translator"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "searches compressed files",
+			input:    []string{"error", "app.log.gz"},
+			expected: []string{"-z", "--max-depth=0", "error", "app.log.gz"},
+		},
+		{
+			name:     "null data is not search zip",
+			input:    []string{"-z", "error", "app.log.gz"},
+			expected: []string{"-z", "--null-data", "--max-depth=0", "error", "app.log.gz"},
+		},
+		{
+			name:     "with flags",
+			input:    []string{"-ic", "error", "a.gz", "b.gz"},
+			expected: []string{"-z", "-i", "-c", "--max-depth=0", "--include-zero", "error", "a.gz", "b.gz"},
+		},
+	}
+
+	tr := &Translator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tr.Translate(tt.input, translator.Options{Mode: "gnu"}).Args
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Translate(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslatorInterface(t *testing.T) {
+	tr := &Translator{}
+
+	if tr.Name() != "zgrep2rg" {
+		t.Errorf("Name() = %q, want %q", tr.Name(), "zgrep2rg")
+	}
+	if tr.SourceTool() != "zgrep" {
+		t.Errorf("SourceTool() = %q, want %q", tr.SourceTool(), "zgrep")
+	}
+	if tr.TargetTool() != "rg" {
+		t.Errorf("TargetTool() = %q, want %q", tr.TargetTool(), "rg")
+	}
+	if !tr.IncludeInInit() {
+		t.Error("IncludeInInit() = false, want true")
+	}
+}